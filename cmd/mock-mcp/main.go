@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/http"
 	"os"
@@ -10,6 +11,16 @@ import (
 )
 
 func main() {
+	transport := flag.String("transport", "http", "Transport to serve MCP over: http or stdio")
+	flag.Parse()
+
+	if *transport != "http" && *transport != "stdio" {
+		log.Fatalf("Unknown transport %q (expected http or stdio)", *transport)
+	}
+
+	// stdout carries the protocol in stdio mode, so all logging must go to stderr
+	log.SetOutput(os.Stderr)
+
 	var configPath string
 	var testcasesDir string
 	var githubSync *mcp.GitHubSync
@@ -64,13 +75,25 @@ func main() {
 	}
 	defer server.Close()
 
+	if *transport == "stdio" {
+		log.Printf("Starting Mock MCP Server on stdio")
+		log.Printf("Watching config file: %s", configPath)
+		if err := server.ServeStdio(os.Stdin, os.Stdout); err != nil {
+			log.Fatal("stdio transport failed:", err)
+		}
+		return
+	}
+
 	http.HandleFunc("/mcp", server.HandleRequest)
+	http.HandleFunc("/sse", server.HandleSSE)
+	http.HandleFunc("/messages", server.HandleMessages)
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
 	http.HandleFunc("/testcase/builder", server.HandleTestCaseBuilder)
 	http.HandleFunc("/api/testcase/save", server.HandleSaveTestCase)
+	http.HandleFunc("/api/journal", server.HandleJournal)
 
 	// Register webhook endpoint if GitHub sync is enabled
 	if githubSync != nil {
@@ -81,12 +104,16 @@ func main() {
 	log.Printf("Starting Mock MCP Server on port %s", port)
 	log.Printf("Watching config file: %s", configPath)
 	log.Printf("Endpoints:")
-	log.Printf("  POST /mcp - MCP protocol endpoint")
-	log.Printf("  GET /mcp?stream=true - Streaming MCP endpoint")
+	log.Printf("  POST /mcp - MCP protocol endpoint (Streamable HTTP, JSON or SSE responses)")
+	log.Printf("  GET /mcp - Server-to-client SSE stream for a session")
+	log.Printf("  DELETE /mcp - End a session")
 	log.Printf("  WS /mcp - WebSocket MCP endpoint")
+	log.Printf("  GET /sse - Legacy HTTP+SSE stream (2024-11-05 transport)")
+	log.Printf("  POST /messages?sessionId=... - Legacy HTTP+SSE message endpoint")
 	log.Printf("  GET /health - Health check")
 	log.Printf("  GET /testcase/builder - Test case builder UI")
 	log.Printf("  POST /api/testcase/save - Save test case API")
+	log.Printf("  GET|DELETE /api/journal - Request journal (records calls and cancellations)")
 	if githubSync != nil {
 		log.Printf("  POST /webhook/github - GitHub webhook endpoint (for auto-sync)")
 		if webhookSecret != "" {
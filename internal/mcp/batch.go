@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// isBatch reports whether a raw JSON-RPC payload is a batch (a JSON array)
+func isBatch(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// processMessage decodes and processes a raw JSON-RPC message or batch
+// It returns the reply to send, or nil if there is none
+func (s *MockMCPServer) processMessage(rc *requestContext, data []byte) interface{} {
+	if isBatch(data) {
+		return s.processBatch(rc, data)
+	}
+
+	var req MCPRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			Error: &MCPError{
+				Code:    -32700,
+				Message: "Parse error",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	if response := s.processRequest(rc, &req); response != nil {
+		return response
+	}
+	return nil
+}
+
+// processBatch processes every message of a JSON-RPC batch in order
+// It returns the array of responses to send, a single error response if the batch itself is
+// invalid, or nil if the batch held only notifications
+func (s *MockMCPServer) processBatch(rc *requestContext, data []byte) interface{} {
+	var messages []json.RawMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			Error: &MCPError{
+				Code:    -32700,
+				Message: "Parse error",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	// Batching was removed from the protocol in 2025-06-18
+	if version := rc.session.ProtocolVersion(); protocolAtLeast(version, ProtocolVersion20250618) {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			Error: &MCPError{
+				Code:    -32600,
+				Message: "Invalid Request",
+				Data:    fmt.Sprintf("JSON-RPC batches are not supported in protocol version %s", version),
+			},
+		}
+	}
+
+	if len(messages) == 0 {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			Error: &MCPError{
+				Code:    -32600,
+				Message: "Invalid Request",
+				Data:    "empty batch",
+			},
+		}
+	}
+
+	responses := make([]*MCPResponse, 0, len(messages))
+	for _, message := range messages {
+		var req MCPRequest
+		if err := json.Unmarshal(message, &req); err != nil || (req.Method == "" && !req.isResponse()) {
+			responses = append(responses, &MCPResponse{
+				JSONRPC: "2.0",
+				Error: &MCPError{
+					Code:    -32600,
+					Message: "Invalid Request",
+				},
+			})
+			continue
+		}
+
+		// initialize negotiates the session, so it has to be sent on its own
+		if req.Method == "initialize" {
+			responses = append(responses, &MCPResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error: &MCPError{
+					Code:    -32600,
+					Message: "Invalid Request",
+					Data:    "initialize must not be part of a batch",
+				},
+			})
+			continue
+		}
+
+		if response := s.processRequest(rc, &req); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
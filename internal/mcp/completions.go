@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// maxCompletionValues is the most values a completion/complete result may hold
+const maxCompletionValues = 100
+
+// Completion modes
+const (
+	CompletionModePrefix   = "prefix"   // Values starting with the partial value (the default)
+	CompletionModeStatic   = "static"   // All values, whatever the partial value
+	CompletionModeTestCase = "testcase" // Values from the first completion test case matching the partial value
+)
+
+// handleComplete handles the completion/complete MCP method
+func (s *MockMCPServer) handleComplete(session *Session, req *MCPRequest) *MCPResponse {
+	var params struct {
+		Ref struct {
+			Type string `json:"type"`
+			Name string `json:"name,omitempty"`
+			URI  string `json:"uri,omitempty"`
+		} `json:"ref"`
+		Argument struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"argument"`
+		Context struct {
+			Arguments map[string]string `json:"arguments,omitempty"`
+		} `json:"context,omitempty"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	// Resolve the reference; refName is the name completion test cases are filed under
+	var ref, refName string
+	switch params.Ref.Type {
+	case "ref/prompt":
+		if _, exists := s.toolManager.GetPrompt(params.Ref.Name); !exists {
+			return invalidCompletionRef(req, fmt.Sprintf("prompt not found: %s", params.Ref.Name))
+		}
+		ref, refName = params.Ref.Name, params.Ref.Name
+	case "ref/resource":
+		template, exists := s.toolManager.GetResourceTemplate(params.Ref.URI)
+		if !exists {
+			return invalidCompletionRef(req, fmt.Sprintf("resource template not found: %s", params.Ref.URI))
+		}
+		ref, refName = params.Ref.URI, template.Name
+	default:
+		return invalidCompletionRef(req, fmt.Sprintf("unsupported ref type: %s", params.Ref.Type))
+	}
+
+	values := []string{}
+	if config, exists := s.toolManager.GetCompletion(params.Ref.Type, ref, params.Argument.Name); exists {
+		values = s.completeValues(session, config, refName, params.Argument.Name, params.Argument.Value, params.Context.Arguments)
+	}
+
+	total := len(values)
+	if total > maxCompletionValues {
+		values = values[:maxCompletionValues]
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"completion": map[string]interface{}{
+				"values":  values,
+				"total":   total,
+				"hasMore": total > len(values),
+			},
+		},
+	}
+}
+
+// completeValues returns the completions for a partial argument value
+func (s *MockMCPServer) completeValues(session *Session, config CompletionConfig, refName, argument, partial string, context map[string]string) []string {
+	switch config.Mode {
+	case CompletionModeStatic:
+		return append([]string{}, config.Values...)
+	case CompletionModeTestCase:
+		// The partial value is matched as "value", alongside any already-resolved arguments
+		args := map[string]interface{}{"value": partial}
+		for name, value := range context {
+			args[name] = value
+		}
+		name := fmt.Sprintf("%s-%s-completion", refName, argument)
+		testCase, err := s.testCaseManager.FindMatchingTestCase(name, args, session.Roots(), 0, "")
+		if err != nil {
+			log.Printf("No completion test case for %s with value %q", name, partial)
+			return []string{}
+		}
+		return append([]string{}, testCase.Completion...)
+	default:
+		values := []string{}
+		for _, value := range config.Values {
+			if strings.HasPrefix(strings.ToLower(value), strings.ToLower(partial)) {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+}
+
+// invalidCompletionRef creates the error response for a completion request whose ref is unknown
+func invalidCompletionRef(req *MCPRequest, data string) *MCPResponse {
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error: &MCPError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    data,
+		},
+	}
+}
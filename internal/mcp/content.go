@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// loadContentFiles resolves the file references of content blocks, relative to baseDir
+// image and audio files are base64-encoded into data, text files fill in text, and resource
+// blocks are embedded as text or blob contents depending on their MIME type
+func loadContentFiles(baseDir string, blocks []ContentBlock) error {
+	for i := range blocks {
+		block := &blocks[i]
+		if block.Resource != nil && block.Resource.File != "" && block.File == "" {
+			block.File = block.Resource.File
+		}
+		if block.File == "" {
+			continue
+		}
+		path := filepath.Join(baseDir, block.File)
+
+		switch block.Type {
+		case "image", "audio":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read content file: %w", err)
+			}
+			block.Data = base64.StdEncoding.EncodeToString(data)
+			if block.MimeType == "" {
+				block.MimeType = mime.TypeByExtension(filepath.Ext(path))
+			}
+		case "resource":
+			uri, mimeType := "", block.MimeType
+			if block.Resource != nil {
+				uri = block.Resource.URI
+				if block.Resource.MimeType != "" {
+					mimeType = block.Resource.MimeType
+				}
+			}
+			contents, err := loadResourceFile(path, mimeType)
+			if err != nil {
+				return err
+			}
+			if uri == "" {
+				uri = "file:///" + filepath.ToSlash(block.File)
+			}
+			contents.URI = uri
+			block.Resource = &contents
+			block.MimeType = ""
+		case "text":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read content file: %w", err)
+			}
+			block.Text = string(data)
+		default:
+			return fmt.Errorf("file is not supported for %q content", block.Type)
+		}
+	}
+	return nil
+}
+
+// contentForVersion drops content blocks the session's protocol version does not know about
+// audio was added in 2025-03-26 and resource_link in 2025-06-18
+func contentForVersion(session *Session, blocks []ContentBlock) []ContentBlock {
+	version := session.ProtocolVersion()
+	if protocolAtLeast(version, ProtocolVersion20250618) {
+		return blocks
+	}
+
+	filtered := make([]ContentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		switch {
+		case block.Type == "resource_link":
+		case block.Type == "audio" && !protocolAtLeast(version, ProtocolVersion20250326):
+		default:
+			filtered = append(filtered, block)
+			continue
+		}
+		log.Printf("Dropping %s content block unsupported in protocol version %s (session %s)", block.Type, version, session.ID())
+	}
+	return filtered
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultElicitationTimeout is how long to wait for the user when the test case sets no timeout
+// It is longer than the sampling timeout because a person has to fill in the form
+const defaultElicitationTimeout = 5 * time.Minute
+
+// elicitationResult is the client's reply to elicitation/create
+type elicitationResult struct {
+	Action  string                 `json:"action"` // accept, decline or cancel
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// runElicitation asks the user for input as scripted by a test case's elicitation step
+// It returns the tool result for the user's action, the {{elicitation.*}} values to fill into it,
+// and whether the user accepted, so that any later step may run
+// Failures on the client side become error results; an error is only returned if the call is cancelled
+func (s *MockMCPServer) runElicitation(rc *requestContext, toolName string, testCase *TestCaseConfig) (ToolResult, map[string]string, bool, error) {
+	step := testCase.Elicitation
+
+	// Elicitation was added in 2025-06-18
+	if version := rc.session.ProtocolVersion(); !protocolAtLeast(version, ProtocolVersion20250618) {
+		log.Printf("Session %s uses protocol %s, which has no elicitation, failing tool %s", rc.session.ID(), version, toolName)
+		return errorResult(fmt.Sprintf("Tool %s needs elicitation, which protocol version %s does not support", toolName, version)), nil, false, nil
+	}
+	if !rc.session.hasClientCapability("elicitation") {
+		log.Printf("Client on session %s does not support elicitation, failing tool %s", rc.session.ID(), toolName)
+		return errorResult(fmt.Sprintf("Tool %s needs elicitation, which the client did not declare", toolName)), nil, false, nil
+	}
+
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = defaultElicitationTimeout
+	}
+
+	params := map[string]interface{}{
+		"message":         step.Message,
+		"requestedSchema": step.RequestedSchema,
+	}
+	raw, err := rc.request("elicitation/create", params, timeout)
+	if err != nil {
+		if rc.ctx.Err() != nil {
+			return ToolResult{}, nil, false, err
+		}
+		log.Printf("Elicitation for tool %s failed: %v", toolName, err)
+		return errorResult(fmt.Sprintf("Elicitation failed: %v", err)), nil, false, nil
+	}
+
+	var reply elicitationResult
+	if err := json.Unmarshal(raw, &reply); err != nil {
+		return errorResult(fmt.Sprintf("Invalid elicitation result: %v", err)), nil, false, nil
+	}
+	log.Printf("Elicitation for tool %s answered with %s: %v", toolName, reply.Action, reply.Content)
+
+	vars := map[string]string{"elicitation.action": reply.Action}
+	switch reply.Action {
+	case "accept":
+		if err := validateSchema(step.RequestedSchema, reply.Content); err != nil {
+			return errorResult(fmt.Sprintf("Elicited content does not match requestedSchema: %v", err)), vars, false, nil
+		}
+		for name, value := range elicitedValues(step.RequestedSchema, reply.Content) {
+			vars["elicitation."+name] = value
+		}
+		if step.Accept != nil {
+			return *step.Accept, vars, true, nil
+		}
+		return testCase.Response, vars, true, nil
+	case "decline":
+		if step.Decline != nil {
+			return *step.Decline, vars, false, nil
+		}
+		return errorResult("The user declined to provide the requested information"), vars, false, nil
+	case "cancel":
+		if step.Cancel != nil {
+			return *step.Cancel, vars, false, nil
+		}
+		return errorResult("The user cancelled the request for information"), vars, false, nil
+	default:
+		return errorResult(fmt.Sprintf("Invalid elicitation action: %q", reply.Action)), vars, false, nil
+	}
+}
+
+// elicitedValues returns the value for each accepted field and each field in the schema's properties
+// A field the user left out takes the schema's default, or is empty so that no placeholder is left behind
+func elicitedValues(schema map[string]interface{}, content map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, property := range properties {
+		values[name] = ""
+		if property, ok := property.(map[string]interface{}); ok {
+			if value, ok := property["default"]; ok {
+				values[name] = fmt.Sprint(value)
+			}
+		}
+	}
+	for name, value := range content {
+		values[name] = fmt.Sprint(value)
+	}
+	return values
+}
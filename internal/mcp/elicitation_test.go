@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestElicitedValues(t *testing.T) {
+	schema := `{properties: {date: {type: string}, attendees: {type: integer, default: 2}, notes: {type: string}}}`
+
+	tests := []struct {
+		name    string
+		content map[string]interface{}
+		want    map[string]string
+	}{
+		{
+			"all answered",
+			map[string]interface{}{"date": "2025-01-01", "attendees": 4.0, "notes": "window"},
+			map[string]string{"date": "2025-01-01", "attendees": "4", "notes": "window"},
+		},
+		{
+			"unanswered fields take the default or are empty",
+			map[string]interface{}{"date": "2025-01-01"},
+			map[string]string{"date": "2025-01-01", "attendees": "2", "notes": ""},
+		},
+		{
+			"fields outside the schema are kept",
+			map[string]interface{}{"room": "Boardroom"},
+			map[string]string{"date": "", "attendees": "2", "notes": "", "room": "Boardroom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := elicitedValues(parseSchema(t, schema), tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("elicitedValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -126,7 +126,8 @@ func (gs *GitHubSync) cloneRepo(destDir string) error {
 	// Use authenticated URL if credentials are provided
 	cloneURL := gs.getAuthenticatedURL()
 	cmd := exec.Command("git", "clone", "--depth", "1", cloneURL, destDir)
-	cmd.Stdout = os.Stdout
+	// git output goes to stderr so it can't corrupt the stdio transport
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("git clone failed: %w", err)
@@ -148,7 +149,7 @@ func (gs *GitHubSync) pullRepo(repoDir string) error {
 	}
 
 	cmd := exec.Command("git", "-C", repoDir, "pull")
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("git pull failed: %w", err)
@@ -172,7 +173,7 @@ func (gs *GitHubSync) copyDirectory(srcDir, destDir string) error {
 	// Use cp command for simplicity (works on Unix-like systems)
 	// For cross-platform, we could use filepath.Walk, but cp is simpler
 	cmd := exec.Command("cp", "-r", srcDir+"/.", destDir+"/")
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		// Fallback to manual copy if cp fails
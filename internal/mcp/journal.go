@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxJournalEntries is the number of entries kept before the oldest are discarded
+const maxJournalEntries = 1000
+
+// JournalEntry records one message handled by the server
+type JournalEntry struct {
+	Time      time.Time              `json:"time"`
+	SessionID string                 `json:"sessionId"`
+	RequestID interface{}            `json:"requestId,omitempty"`
+	Method    string                 `json:"method"`
+	Tool      string                 `json:"tool,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Status    string                 `json:"status"` // ok, error, cancelled or received
+	Error     string                 `json:"error,omitempty"`
+	Reason    string                 `json:"reason,omitempty"` // cancellation reason supplied by the client
+	Roots     []Root                 `json:"roots,omitempty"`  // roots returned by the client for roots/list
+}
+
+// RequestJournal keeps a bounded, in-memory history of handled requests so tests can
+// assert on what a client actually sent
+type RequestJournal struct {
+	entries []JournalEntry
+	mutex   sync.RWMutex
+}
+
+// NewRequestJournal creates an empty request journal
+func NewRequestJournal() *RequestJournal {
+	return &RequestJournal{}
+}
+
+// Record appends an entry to the journal (thread-safe)
+func (j *RequestJournal) Record(entry JournalEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.entries = append(j.entries, entry)
+	if len(j.entries) > maxJournalEntries {
+		j.entries = j.entries[len(j.entries)-maxJournalEntries:]
+	}
+}
+
+// Entries returns the recorded entries, optionally limited to one session (thread-safe)
+func (j *RequestJournal) Entries(sessionID string) []JournalEntry {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	entries := make([]JournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		if sessionID == "" || entry.SessionID == sessionID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Clear removes all entries (thread-safe)
+func (j *RequestJournal) Clear() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.entries = nil
+}
+
+// HandleJournal serves the request journal
+// GET returns the entries (filtered by ?sessionId= if given), DELETE clears them
+func (s *MockMCPServer) HandleJournal(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"entries": s.journal.Entries(r.URL.Query().Get("sessionId")),
+		})
+	case http.MethodDelete:
+		s.journal.Clear()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
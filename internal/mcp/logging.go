@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// logLevelSeverity orders the syslog levels used by MCP logging, from least to most severe
+var logLevelSeverity = map[string]int{
+	"debug":     0,
+	"info":      1,
+	"notice":    2,
+	"warning":   3,
+	"error":     4,
+	"critical":  5,
+	"alert":     6,
+	"emergency": 7,
+}
+
+// isValidLogLevel reports whether level is one of the MCP logging levels
+func isValidLogLevel(level string) bool {
+	_, ok := logLevelSeverity[level]
+	return ok
+}
+
+// logLevelEnabled reports whether a message at level passes the minimum level chosen by the client
+// Every message passes until the client has chosen a level
+func logLevelEnabled(level, minimum string) bool {
+	if minimum == "" {
+		return true
+	}
+	return logLevelSeverity[level] >= logLevelSeverity[minimum]
+}
+
+// handleSetLogLevel handles the logging/setLevel MCP method
+func (s *MockMCPServer) handleSetLogLevel(session *Session, req *MCPRequest) *MCPResponse {
+	var params struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || !isValidLogLevel(params.Level) {
+		data := fmt.Sprintf("invalid log level: %q", params.Level)
+		if err != nil {
+			data = err.Error()
+		}
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    data,
+			},
+		}
+	}
+
+	session.setLogLevel(params.Level)
+	log.Printf("Session %s set log level to %s", session.ID(), params.Level)
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]interface{}{},
+	}
+}
+
+// sendLogMessages runs a test case's log script, sending notifications/message for each
+// line at or above the session's log level
+func (rc *requestContext) sendLogMessages(messages []LogMessage) error {
+	for _, message := range messages {
+		if err := sleepContext(rc.ctx, message.Delay); err != nil {
+			return err
+		}
+		if !isValidLogLevel(message.Level) {
+			log.Printf("Skipping log message with invalid level %q", message.Level)
+			continue
+		}
+		if !logLevelEnabled(message.Level, rc.session.LogLevel()) {
+			continue
+		}
+
+		params := map[string]interface{}{
+			"level": message.Level,
+			"data":  message.Data,
+		}
+		if message.Logger != "" {
+			params["logger"] = message.Logger
+		}
+		rc.notify("notifications/message", params)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package mcp
+
+import (
+	"log"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Match modes, chosen per test case or per tool
+const (
+	MatchModeSubset = "subset" // Arguments not listed in input are ignored (the default)
+	MatchModeExact  = "exact"  // Arguments not listed in input prevent a match
+)
+
+// exactMatch reports whether a test case is matched in exact mode
+// The test case's own mode wins over the tool's
+func (tcm *TestCaseManager) exactMatch(testCase *TestCaseConfig, toolMode string) bool {
+	mode := testCase.Match
+	if mode == "" {
+		mode = toolMode
+	}
+	switch mode {
+	case "", MatchModeSubset:
+		return false
+	case MatchModeExact:
+		return true
+	default:
+		log.Printf("Unknown match mode %q, using %s", mode, MatchModeSubset)
+		return false
+	}
+}
+
+// extraArguments returns the sorted names of the arguments that the expected input does not list
+func extraArguments(expected, actual map[string]interface{}) []string {
+	var extra []string
+	for key := range actual {
+		if _, listed := expected[key]; !listed {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	return extra
+}
+
+// matcherOperators are the operators that matchOperator understands
+var matcherOperators = map[string]bool{
+	"$exists": true, "$any": true, "$eq": true, "$ne": true, "$in": true, "$nin": true,
+	"$gt": true, "$gte": true, "$lt": true, "$lte": true, "$regex": true, "$type": true,
+	"$subset": true, "$exact": true, "$ordered": true, "$unordered": true, "$contains": true, "$not": true,
+}
+
+// isOperatorObject reports whether an expected test-case value is a matcher such as
+// {$regex: "^hello"} rather than a literal, i.e. a map whose keys are all known operators
+// Other maps, such as {$schema: ...} or {$ref: ...}, are matched as literal objects
+func isOperatorObject(expected interface{}) (map[string]interface{}, bool) {
+	ops, ok := expected.(map[string]interface{})
+	if !ok || len(ops) == 0 {
+		return nil, false
+	}
+	for key := range ops {
+		if !matcherOperators[key] {
+			return nil, false
+		}
+	}
+	return ops, true
+}
+
+// compilePatterns compiles every $regex pattern in an expected test-case value into patterns,
+// logging invalid patterns once, when the test case is loaded
+func compilePatterns(expected interface{}, patterns map[string]*regexp.Regexp, testCase string) {
+	switch value := expected.(type) {
+	case map[string]interface{}:
+		if pattern, ok := value["$regex"].(string); ok {
+			if _, done := patterns[pattern]; !done {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					log.Printf("Warning: invalid $regex pattern %q in test case %s: %v", pattern, testCase, err)
+				}
+				patterns[pattern] = re
+			}
+		}
+		for _, nested := range value {
+			compilePatterns(nested, patterns, testCase)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			compilePatterns(nested, patterns, testCase)
+		}
+	}
+}
+
+// compiledPattern returns the compiled form of a $regex pattern, or nil if it is invalid
+// Patterns are compiled when test cases load; any other pattern is compiled and cached on first use
+func (tcm *TestCaseManager) compiledPattern(pattern string) *regexp.Regexp {
+	tcm.indexMutex.RLock()
+	re, cached := tcm.patterns[pattern]
+	tcm.indexMutex.RUnlock()
+	if cached {
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		log.Printf("Invalid $regex pattern %q: %v", pattern, err)
+	}
+	tcm.indexMutex.Lock()
+	tcm.patterns[pattern] = re
+	tcm.indexMutex.Unlock()
+	return re
+}
+
+// matchOperators checks an argument against every operator of a matcher
+// exists is false when the argument was not sent, which only $exists: false accepts
+func (tcm *TestCaseManager) matchOperators(ops map[string]interface{}, actual interface{}, exists bool) bool {
+	if want, ok := ops["$exists"]; ok {
+		if wantBool, _ := want.(bool); wantBool != exists {
+			return false
+		}
+	}
+	if !exists {
+		_, onlyExists := ops["$exists"]
+		return onlyExists && len(ops) == 1
+	}
+
+	for op, operand := range ops {
+		if !tcm.matchOperator(op, operand, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchOperator checks an argument against a single operator
+func (tcm *TestCaseManager) matchOperator(op string, operand, actual interface{}) bool {
+	switch op {
+	case "$exists":
+		return true // checked by matchOperators
+	case "$any":
+		any, _ := operand.(bool)
+		return any
+	case "$eq":
+		return tcm.valuesMatch(operand, actual)
+	case "$ne":
+		return !tcm.valuesMatch(operand, actual)
+	case "$in", "$nin":
+		options, ok := operand.([]interface{})
+		if !ok {
+			log.Printf("Invalid %s operand %v: expected a list", op, operand)
+			return false
+		}
+		found := false
+		for _, option := range options {
+			if tcm.valuesMatch(option, actual) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in")
+	case "$gt", "$gte", "$lt", "$lte":
+		bound, boundOK := tcm.toFloat64(operand)
+		value, valueOK := tcm.toFloat64(actual)
+		if !boundOK {
+			log.Printf("Invalid %s operand %v: expected a number", op, operand)
+			return false
+		}
+		if !valueOK {
+			return false
+		}
+		switch op {
+		case "$gt":
+			return value > bound
+		case "$gte":
+			return value >= bound
+		case "$lt":
+			return value < bound
+		default:
+			return value <= bound
+		}
+	case "$regex":
+		pattern, ok := operand.(string)
+		if !ok {
+			log.Printf("Invalid $regex operand %v: expected a string", operand)
+			return false
+		}
+		re := tcm.compiledPattern(pattern)
+		text, ok := actual.(string)
+		return ok && re != nil && re.MatchString(text)
+	case "$type":
+		name, ok := operand.(string)
+		if !ok {
+			log.Printf("Invalid $type operand %v: expected a type name", operand)
+			return false
+		}
+		return tcm.matchType(name, actual)
+	case "$subset", "$exact":
+		expected, ok := operand.(map[string]interface{})
+		if !ok {
+			log.Printf("Invalid %s operand %v: expected an object", op, operand)
+			return false
+		}
+		return tcm.matchObject(expected, actual, op == "$exact")
+	case "$ordered", "$unordered":
+		expected, ok := operand.([]interface{})
+		if !ok {
+			log.Printf("Invalid %s operand %v: expected a list", op, operand)
+			return false
+		}
+		if op == "$ordered" {
+			return tcm.matchOrdered(expected, actual)
+		}
+		return tcm.matchUnordered(expected, actual, false)
+	case "$contains":
+		// A string operand is a substring test, a list operand an array containment test
+		if substring, ok := operand.(string); ok {
+			text, ok := actual.(string)
+			return ok && strings.Contains(text, substring)
+		}
+		expected, ok := operand.([]interface{})
+		if !ok {
+			log.Printf("Invalid $contains operand %v: expected a string or a list", operand)
+			return false
+		}
+		return tcm.matchUnordered(expected, actual, true)
+	case "$not":
+		ops, ok := isOperatorObject(operand)
+		if !ok {
+			return !tcm.valuesMatch(operand, actual)
+		}
+		return !tcm.matchOperators(ops, actual, true)
+	default:
+		log.Printf("Unknown matcher operator %s", op)
+		return false
+	}
+}
+
+// matchType reports whether an argument has the named JSON type
+// (string, number, integer, boolean, array, object or null)
+func (tcm *TestCaseManager) matchType(name string, actual interface{}) bool {
+	switch name {
+	case "string":
+		_, ok := actual.(string)
+		return ok
+	case "number":
+		_, ok := tcm.toFloat64(actual)
+		return ok
+	case "integer":
+		value, ok := tcm.toFloat64(actual)
+		return ok && value == math.Trunc(value)
+	case "boolean":
+		_, ok := actual.(bool)
+		return ok
+	case "array":
+		return actual != nil && reflect.TypeOf(actual).Kind() == reflect.Slice
+	case "object":
+		return actual != nil && reflect.TypeOf(actual).Kind() == reflect.Map
+	case "null":
+		return actual == nil
+	default:
+		log.Printf("Unknown $type %q", name)
+		return false
+	}
+}
+
+// matchObject checks an object argument against an expected object, recursively
+// Every expected key must be present and match; with exact, the argument must have no other keys
+func (tcm *TestCaseManager) matchObject(expected map[string]interface{}, actual interface{}, exact bool) bool {
+	object, ok := actual.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if exact {
+		for key := range object {
+			if _, expectedKey := expected[key]; !expectedKey {
+				return false
+			}
+		}
+	}
+	return tcm.matchArguments(expected, object)
+}
+
+// matchOrdered checks an array argument element by element against an expected array of the same length
+func (tcm *TestCaseManager) matchOrdered(expected []interface{}, actual interface{}) bool {
+	items, ok := actual.([]interface{})
+	if !ok || len(items) != len(expected) {
+		return false
+	}
+	for i := range expected {
+		if !tcm.valuesMatch(expected[i], items[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchUnordered checks that each expected element matches a different element of an array argument
+// Without contains the array must have exactly as many elements as expected; with contains it may have more
+func (tcm *TestCaseManager) matchUnordered(expected []interface{}, actual interface{}, contains bool) bool {
+	items, ok := actual.([]interface{})
+	if !ok || len(items) < len(expected) || (!contains && len(items) != len(expected)) {
+		return false
+	}
+	used := make([]bool, len(items))
+	return tcm.assignElements(expected, items, used)
+}
+
+// assignElements pairs each expected element with an unused matching element, backtracking when
+// an early pairing leaves a later element without a match
+func (tcm *TestCaseManager) assignElements(expected, items []interface{}, used []bool) bool {
+	if len(expected) == 0 {
+		return true
+	}
+	for i, item := range items {
+		if used[i] || !tcm.valuesMatch(expected[0], item) {
+			continue
+		}
+		used[i] = true
+		if tcm.assignElements(expected[1:], items, used) {
+			return true
+		}
+		used[i] = false
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package mcp
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// newTestMatcher returns a test case manager with no test cases, for exercising the matchers
+func newTestMatcher() *TestCaseManager {
+	return &TestCaseManager{
+		index:    make(map[string][]indexedTestCase),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+}
+
+// parseInput decodes an expected test-case input the way test case files are decoded
+func parseInput(t *testing.T, input string) map[string]interface{} {
+	t.Helper()
+	var expected map[string]interface{}
+	if err := yaml.Unmarshal([]byte(input), &expected); err != nil {
+		t.Fatalf("invalid input YAML %q: %v", input, err)
+	}
+	return expected
+}
+
+// parseArguments decodes tool call arguments the way they arrive in tools/call
+func parseArguments(t *testing.T, args string) map[string]interface{} {
+	t.Helper()
+	var actual map[string]interface{}
+	if err := json.Unmarshal([]byte(args), &actual); err != nil {
+		t.Fatalf("invalid arguments JSON %q: %v", args, err)
+	}
+	return actual
+}
+
+func TestMatchOperators(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string // test-case input, as YAML
+		args  string // tool call arguments, as JSON
+		want  bool
+	}{
+		{"eq", `{a: {$eq: 1}}`, `{"a": 1}`, true},
+		{"eq mismatch", `{a: {$eq: 1}}`, `{"a": 2}`, false},
+		{"ne", `{a: {$ne: "x"}}`, `{"a": "y"}`, true},
+		{"ne equal", `{a: {$ne: "x"}}`, `{"a": "x"}`, false},
+
+		{"in", `{op: {$in: [add, subtract]}}`, `{"op": "add"}`, true},
+		{"in mismatch", `{op: {$in: [add, subtract]}}`, `{"op": "divide"}`, false},
+		{"in compares YAML ints with JSON numbers", `{n: {$in: [1, 2]}}`, `{"n": 2.0}`, true},
+		{"in with a non-list operand", `{op: {$in: add}}`, `{"op": "add"}`, false},
+		{"nin", `{op: {$nin: [divide]}}`, `{"op": "add"}`, true},
+		{"nin mismatch", `{op: {$nin: [divide]}}`, `{"op": "divide"}`, false},
+
+		{"gt", `{n: {$gt: 5}}`, `{"n": 6}`, true},
+		{"gt at the bound", `{n: {$gt: 5}}`, `{"n": 5}`, false},
+		{"gte at the bound", `{n: {$gte: 5}}`, `{"n": 5}`, true},
+		{"lt", `{n: {$lt: 5}}`, `{"n": 4.5}`, true},
+		{"lt at the bound", `{n: {$lt: 5}}`, `{"n": 5}`, false},
+		{"lte at the bound", `{n: {$lte: 5}}`, `{"n": 5}`, true},
+		{"range", `{n: {$gte: 0, $lte: 100}}`, `{"n": 50}`, true},
+		{"range below", `{n: {$gte: 0, $lte: 100}}`, `{"n": -1}`, false},
+		{"range on a string", `{n: {$gte: 0}}`, `{"n": "5"}`, false},
+		{"range with a non-numeric bound", `{n: {$gt: five}}`, `{"n": 6}`, false},
+
+		{"regex", `{m: {$regex: "(?i)^hello"}}`, `{"m": "Hello there"}`, true},
+		{"regex mismatch", `{m: {$regex: "^hello"}}`, `{"m": "say hello"}`, false},
+		{"regex on a number", `{m: {$regex: "1"}}`, `{"m": 1}`, false},
+		{"regex with an invalid pattern", `{m: {$regex: "([a-"}}`, `{"m": "a"}`, false},
+
+		{"type string", `{v: {$type: string}}`, `{"v": "x"}`, true},
+		{"type number", `{v: {$type: number}}`, `{"v": 1.5}`, true},
+		{"type integer", `{v: {$type: integer}}`, `{"v": 3}`, true},
+		{"type integer written as a float", `{v: {$type: integer}}`, `{"v": 3.0}`, true},
+		{"type integer with a fraction", `{v: {$type: integer}}`, `{"v": 3.5}`, false},
+		{"type boolean", `{v: {$type: boolean}}`, `{"v": false}`, true},
+		{"type array", `{v: {$type: array}}`, `{"v": [1]}`, true},
+		{"type object", `{v: {$type: object}}`, `{"v": {"a": 1}}`, true},
+		{"type null", `{v: {$type: "null"}}`, `{"v": null}`, true},
+		{"type mismatch", `{v: {$type: string}}`, `{"v": 1}`, false},
+		{"unknown type", `{v: {$type: date}}`, `{"v": "2025-01-01"}`, false},
+
+		{"exists", `{a: {$exists: true}}`, `{"a": null}`, true},
+		{"exists but missing", `{a: {$exists: true}}`, `{}`, false},
+		{"exists false and missing", `{a: {$exists: false}}`, `{}`, true},
+		{"exists false but sent", `{a: {$exists: false}}`, `{"a": 1}`, false},
+		{"exists false with other operators and missing", `{a: {$exists: false, $type: string}}`, `{}`, false},
+		{"other operators and missing", `{a: {$type: string}}`, `{}`, false},
+
+		{"any", `{a: {$any: true}}`, `{"a": [1, 2]}`, true},
+		{"any but missing", `{a: {$any: true}}`, `{}`, false},
+		{"any false", `{a: {$any: false}}`, `{"a": 1}`, false},
+
+		{"not literal", `{a: {$not: "x"}}`, `{"a": "y"}`, true},
+		{"not literal equal", `{a: {$not: "x"}}`, `{"a": "x"}`, false},
+		{"not matcher", `{a: {$not: {$regex: "^tmp"}}}`, `{"a": "src/main.go"}`, true},
+		{"not matcher matching", `{a: {$not: {$regex: "^tmp"}}}`, `{"a": "tmp/x"}`, false},
+
+		{"unknown operator is a literal key", `{a: {$near: 1}}`, `{"a": 1}`, false},
+		{"unknown operator matches literally", `{a: {$near: 1}}`, `{"a": {"$near": 1}}`, true},
+		{"dollar keys match literally", `{doc: {$schema: "https://json-schema.org/draft/2020-12/schema", $ref: "#/defs/a"}}`, `{"doc": {"$schema": "https://json-schema.org/draft/2020-12/schema", "$ref": "#/defs/a", "title": "A"}}`, true},
+		{"dollar keys mismatch", `{doc: {$ref: "#/defs/a"}}`, `{"doc": {"$ref": "#/defs/b"}}`, false},
+		{"dollar keys with an operator", `{doc: {$ref: {$regex: "^#/defs/"}}}`, `{"doc": {"$ref": "#/defs/b"}}`, true},
+		{"operators alongside literals", `{a: 1, b: {$gt: 1}}`, `{"a": 1, "b": 2, "c": 3}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tcm := newTestMatcher()
+			got := tcm.matchArguments(parseInput(t, tt.input), parseArguments(t, tt.args))
+			if got != tt.want {
+				t.Errorf("input %s with args %s: got %v, want %v", tt.input, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOperatorObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		want     bool
+	}{
+		{"operators", map[string]interface{}{"$gt": 1, "$lt": 5}, true},
+		{"literal object", map[string]interface{}{"id": 1}, false},
+		{"mixed keys", map[string]interface{}{"$gt": 1, "id": 1}, false},
+		{"unknown dollar keys", map[string]interface{}{"$schema": "x", "$ref": "#/a"}, false},
+		{"operator and unknown dollar key", map[string]interface{}{"$gt": 1, "$ref": "#/a"}, false},
+		{"empty object", map[string]interface{}{}, false},
+		{"string", "$gt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := isOperatorObject(tt.expected); got != tt.want {
+				t.Errorf("isOperatorObject(%v) = %v, want %v", tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePatterns(t *testing.T) {
+	input := parseInput(t, `
+message: {$regex: "^hello"}
+filter:
+  tags: [{$regex: "^v[0-9]+$"}]
+  owner: {$not: {$regex: "([a-"}}
+`)
+	patterns := make(map[string]*regexp.Regexp)
+	compilePatterns(input, patterns, "test")
+
+	for _, pattern := range []string{"^hello", "^v[0-9]+$"} {
+		if re, ok := patterns[pattern]; !ok || re == nil {
+			t.Errorf("pattern %q was not compiled", pattern)
+		}
+	}
+	if re, ok := patterns["([a-"]; !ok || re != nil {
+		t.Errorf("invalid pattern should be cached as nil, got %v (cached: %v)", re, ok)
+	}
+}
+
+func TestNestedMatching(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string // test-case input, as YAML
+		args  string // tool call arguments, as JSON
+		want  bool
+	}{
+		{"object subset", `{f: {customer: {id: c1}}}`, `{"f": {"customer": {"id": "c1", "tier": "gold"}, "page": 2}}`, true},
+		{"object missing key", `{f: {customer: {id: c1}}}`, `{"f": {"customer": {"tier": "gold"}}}`, false},
+		{"object value mismatch", `{f: {customer: {id: c1}}}`, `{"f": {"customer": {"id": "c2"}}}`, false},
+		{"object against a string", `{f: {id: c1}}`, `{"f": "c1"}`, false},
+		{"object with nested operators", `{f: {total: {min: {$gte: 100}}}}`, `{"f": {"total": {"min": 150, "max": 900}}}`, true},
+		{"explicit subset", `{f: {$subset: {id: c1}}}`, `{"f": {"id": "c1", "x": 1}}`, true},
+		{"exact", `{f: {$exact: {id: c1, tier: gold}}}`, `{"f": {"id": "c1", "tier": "gold"}}`, true},
+		{"exact with an extra key", `{f: {$exact: {id: c1}}}`, `{"f": {"id": "c1", "tier": "gold"}}`, false},
+		{"exact empty object", `{f: {$exact: {}}}`, `{"f": {}}`, true},
+		{"exact empty object with a key", `{f: {$exact: {}}}`, `{"f": {"id": "c1"}}`, false},
+		{"exact only applies at its level", `{f: {$exact: {c: {id: c1}}}}`, `{"f": {"c": {"id": "c1", "tier": "gold"}}}`, true},
+		{"exact with an operator inside", `{f: {$exact: {id: {$regex: "^c"}}}}`, `{"f": {"id": "c9"}}`, true},
+
+		{"array ordered", `{s: [date, id]}`, `{"s": ["date", "id"]}`, true},
+		{"array out of order", `{s: [date, id]}`, `{"s": ["id", "date"]}`, false},
+		{"array longer", `{s: [date]}`, `{"s": ["date", "id"]}`, false},
+		{"array shorter", `{s: [date, id]}`, `{"s": ["date"]}`, false},
+		{"array against a string", `{s: [date]}`, `{"s": "date"}`, false},
+		{"array of numbers", `{n: [1, 2]}`, `{"n": [1.0, 2.0]}`, true},
+		{"array of objects", `{f: [{id: 1}, {id: 2}]}`, `{"f": [{"id": 1, "x": true}, {"id": 2}]}`, true},
+		{"array with operators", `{n: [{$gt: 0}, {$type: string}]}`, `{"n": [5, "x"]}`, true},
+		{"explicit ordered", `{s: {$ordered: [a, b]}}`, `{"s": ["a", "b"]}`, true},
+		{"empty array", `{s: []}`, `{"s": []}`, true},
+
+		{"unordered", `{s: {$unordered: [open, shipped]}}`, `{"s": ["shipped", "open"]}`, true},
+		{"unordered with an extra element", `{s: {$unordered: [open, shipped]}}`, `{"s": ["shipped", "open", "x"]}`, false},
+		{"unordered with a missing element", `{s: {$unordered: [open, shipped]}}`, `{"s": ["open", "open"]}`, false},
+		{"unordered duplicates", `{s: {$unordered: [a, a, b]}}`, `{"s": ["a", "b", "a"]}`, true},
+
+		{"contains", `{s: {$contains: [urgent]}}`, `{"s": ["low", "urgent"]}`, true},
+		{"contains missing", `{s: {$contains: [urgent]}}`, `{"s": ["low"]}`, false},
+		{"contains needs distinct elements", `{s: {$contains: [a, a]}}`, `{"s": ["a", "b"]}`, false},
+		{"contains against a string", `{s: {$contains: [urgent]}}`, `{"s": "urgent"}`, false},
+		{"contains substring", `{m: {$contains: "ell"}}`, `{"m": "hello"}`, true},
+		{"contains substring missing", `{m: {$contains: "xyz"}}`, `{"m": "hello"}`, false},
+		{"contains with a non-list operand", `{s: {$contains: 1}}`, `{"s": [1]}`, false},
+
+		// The first expected element could take either actual element, but only 3 leaves 2 for the second
+		{"unordered backtracks", `{n: {$unordered: [{$gt: 1}, 2]}}`, `{"n": [2, 3]}`, true},
+		{"contains backtracks", `{n: {$contains: [{$gte: 2}, 2]}}`, `{"n": [2, 1, 5]}`, true},
+		{"contains cannot assign", `{n: {$contains: [{$gte: 2}, 2]}}`, `{"n": [2, 1]}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tcm := newTestMatcher()
+			got := tcm.matchArguments(parseInput(t, tt.input), parseArguments(t, tt.args))
+			if got != tt.want {
+				t.Errorf("input %s with args %s: got %v, want %v", tt.input, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValuesMatchUncomparable(t *testing.T) {
+	// Values that == cannot compare must not panic
+	tcm := newTestMatcher()
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		want     bool
+	}{
+		{"nil and nil", nil, nil, true},
+		{"nil and a map", nil, map[string]interface{}{}, false},
+		{"string and a slice", "a", []interface{}{"a"}, false},
+		{"typed slice", []string{"a"}, []string{"a"}, true},
+		{"typed map", map[string]int{"a": 1}, map[string]int{"a": 2}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tcm.valuesMatch(tt.expected, tt.actual); got != tt.want {
+				t.Errorf("valuesMatch(%v, %v) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		args  string
+		want  []string
+	}{
+		{"none", `{a: 1, b: 2}`, `{"a": 1}`, nil},
+		{"sorted", `{a: 1}`, `{"z": 1, "a": 1, "m": 2}`, []string{"m", "z"}},
+		{"empty input", `{}`, `{"a": 1}`, []string{"a"}},
+		{"listed as not sent", `{a: {$exists: false}}`, `{"a": 1}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extraArguments(parseInput(t, tt.input), parseArguments(t, tt.args))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestExactMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		caseMode string
+		toolMode string
+		want     bool
+	}{
+		{"default", "", "", false},
+		{"tool exact", "", MatchModeExact, true},
+		{"test case exact", MatchModeExact, "", true},
+		{"test case overrides tool", MatchModeSubset, MatchModeExact, false},
+		{"unknown mode", "strict", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tcm := newTestMatcher()
+			if got := tcm.exactMatch(&TestCaseConfig{Match: tt.caseMode}, tt.toolMode); got != tt.want {
+				t.Errorf("exactMatch(%q, %q) = %v, want %v", tt.caseMode, tt.toolMode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMatchingTestCaseExactMode(t *testing.T) {
+	tcm := newTestMatcher()
+	tcm.index["search"] = []indexedTestCase{
+		{number: 1, file: "search-test-case-1.yaml", testCase: &TestCaseConfig{
+			Name:  "exact query",
+			Match: MatchModeExact,
+			Input: parseInput(t, `{query: foo}`),
+		}},
+		{number: 2, file: "search-test-case-2.yaml", testCase: &TestCaseConfig{
+			Name:  "no arguments",
+			Input: parseInput(t, `{}`),
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		args     string
+		toolMode string
+		want     string // name of the matched test case, "" for no match
+	}{
+		{"exact arguments", `{"query": "foo"}`, "", "exact query"},
+		{"extra argument skips the exact test case", `{"query": "foo", "limit": 5}`, "", "no arguments"},
+		{"empty input matches anything in subset mode", `{"other": 1}`, "", "no arguments"},
+		{"empty input only matches no arguments in exact mode", `{"other": 1}`, MatchModeExact, ""},
+		{"no arguments in exact mode", `{}`, MatchModeExact, "no arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCase, err := tcm.FindMatchingTestCase("search", parseArguments(t, tt.args), nil, 0, tt.toolMode)
+			got := ""
+			if err == nil {
+				got = testCase.Name
+			}
+			if got != tt.want {
+				t.Errorf("args %s with tool mode %q matched %q, want %q", tt.args, tt.toolMode, got, tt.want)
+			}
+		})
+	}
+}
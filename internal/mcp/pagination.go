@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pageBounds resolves the cursor of a */list request to the range [start, end) of the
+// list's items to return, and the cursor of the following page ("" on the last page)
+// Every item is returned in one page when no page size is configured
+func (s *MockMCPServer) pageBounds(req *MCPRequest, list string, total int) (int, int, string, *MCPError) {
+	var params struct {
+		Cursor string `json:"cursor,omitempty"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return 0, 0, "", &MCPError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+		}
+	}
+
+	start := 0
+	if params.Cursor != "" {
+		offset, err := decodeCursor(list, params.Cursor)
+		if err != nil {
+			return 0, 0, "", &MCPError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+		}
+		start = offset
+		if start > total {
+			start = total
+		}
+	}
+
+	pageSize := s.toolManager.GetServerConfig().PageSize
+	if pageSize <= 0 {
+		return start, total, "", nil
+	}
+
+	end := start + pageSize
+	if end >= total {
+		return start, total, "", nil
+	}
+	return start, end, encodeCursor(list, end), nil
+}
+
+// encodeCursor builds the opaque cursor pointing at an offset in a list
+func encodeCursor(list string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d", list, offset)))
+}
+
+// decodeCursor returns the offset a cursor points at, rejecting cursors issued for another list
+func decodeCursor(list, cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	name, value, found := strings.Cut(string(data), ":")
+	if !found || name != list {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
+}
+
+// listResult builds a */list result holding one page of items under the given key
+func listResult(key string, items interface{}, nextCursor string) map[string]interface{} {
+	result := map[string]interface{}{
+		key: items,
+	}
+	if nextCursor != "" {
+		result["nextCursor"] = nextCursor
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, offset := range []int{0, 1, 50, 1000000} {
+		cursor := encodeCursor("tools", offset)
+		got, err := decodeCursor("tools", cursor)
+		if err != nil || got != offset {
+			t.Errorf("decodeCursor(encodeCursor(%d)) = %d, %v", offset, got, err)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsMalformed(t *testing.T) {
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("tools:1"))},
+		{"no separator", raw("tools1")},
+		{"another list", encodeCursor("prompts", 2)},
+		{"empty list name", raw(":2")},
+		{"not a number", raw("tools:two")},
+		{"negative offset", raw("tools:-1")},
+		{"empty offset", raw("tools:")},
+		{"trailing data", raw("tools:2:3")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if offset, err := decodeCursor("tools", tt.cursor); err == nil {
+				t.Errorf("decodeCursor(%q) = %d, want an error", tt.cursor, offset)
+			}
+		})
+	}
+}
+
+func TestPageBounds(t *testing.T) {
+	paged := &MockMCPServer{toolManager: &ToolManager{server: ServerConfig{PageSize: 2}}}
+	unpaged := &MockMCPServer{toolManager: &ToolManager{}}
+
+	request := func(cursor string) *MCPRequest {
+		if cursor == "" {
+			return &MCPRequest{}
+		}
+		params, _ := json.Marshal(map[string]string{"cursor": cursor})
+		return &MCPRequest{Params: params}
+	}
+
+	tests := []struct {
+		name       string
+		server     *MockMCPServer
+		cursor     string
+		total      int
+		wantStart  int
+		wantEnd    int
+		wantCursor string
+	}{
+		{"first page", paged, "", 5, 0, 2, encodeCursor("tools", 2)},
+		{"middle page", paged, encodeCursor("tools", 2), 5, 2, 4, encodeCursor("tools", 4)},
+		{"last page", paged, encodeCursor("tools", 4), 5, 4, 5, ""},
+		{"exact last page", paged, encodeCursor("tools", 2), 4, 2, 4, ""},
+		{"offset past the end", paged, encodeCursor("tools", 9), 5, 5, 5, ""},
+		{"empty list", paged, "", 0, 0, 0, ""},
+		{"no page size", unpaged, "", 5, 0, 5, ""},
+		{"no page size with a cursor", unpaged, encodeCursor("tools", 3), 5, 3, 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, next, err := tt.server.pageBounds(request(tt.cursor), "tools", tt.total)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err.Data)
+			}
+			if start != tt.wantStart || end != tt.wantEnd || next != tt.wantCursor {
+				t.Errorf("got [%d, %d) next %q, want [%d, %d) next %q", start, end, next, tt.wantStart, tt.wantEnd, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestPageBoundsInvalidParams(t *testing.T) {
+	server := &MockMCPServer{toolManager: &ToolManager{server: ServerConfig{PageSize: 2}}}
+
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"malformed params", `{"cursor": 1}`},
+		{"malformed cursor", `{"cursor": "!!!"}`},
+		{"cursor for another list", `{"cursor": "` + encodeCursor("prompts", 2) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := server.pageBounds(&MCPRequest{Params: json.RawMessage(tt.params)}, "tools", 5)
+			if err == nil || err.Code != -32602 {
+				t.Errorf("pageBounds(%s) error = %v, want -32602", tt.params, err)
+			}
+		})
+	}
+}
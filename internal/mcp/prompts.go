@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// handleListPrompts handles the prompts/list MCP method
+func (s *MockMCPServer) handleListPrompts(req *MCPRequest) *MCPResponse {
+	configs := s.toolManager.GetAllPrompts()
+	start, end, nextCursor, pageErr := s.pageBounds(req, "prompts", len(configs))
+	if pageErr != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   pageErr,
+		}
+	}
+
+	prompts := make([]Prompt, 0, end-start)
+	for _, config := range configs[start:end] {
+		prompts = append(prompts, Prompt{
+			Name:        config.Name,
+			Description: config.Description,
+			Arguments:   config.Arguments,
+		})
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  listResult("prompts", prompts, nextCursor),
+	}
+}
+
+// handleGetPrompt handles the prompts/get MCP method
+func (s *MockMCPServer) handleGetPrompt(session *Session, req *MCPRequest) *MCPResponse {
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	prompt, exists := s.toolManager.GetPrompt(params.Name)
+	if !exists {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Prompt not found",
+				Data:    params.Name,
+			},
+		}
+	}
+
+	// Check required arguments are present
+	for _, arg := range prompt.Arguments {
+		if _, ok := params.Arguments[arg.Name]; arg.Required && !ok {
+			return &MCPResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error: &MCPError{
+					Code:    -32602,
+					Message: "Invalid params",
+					Data:    fmt.Sprintf("missing required argument: %s", arg.Name),
+				},
+			}
+		}
+	}
+
+	args := make(map[string]interface{}, len(params.Arguments))
+	for key, value := range params.Arguments {
+		args[key] = value
+	}
+
+	// Choose the messages by matching the arguments against the prompt's test cases
+	testCase, err := s.testCaseManager.FindMatchingTestCase(prompt.Name, args, session.Roots(), prompt.DefaultTestCase, "")
+	if err != nil || len(testCase.Messages) == 0 {
+		if err == nil {
+			err = fmt.Errorf("matched test case has no messages")
+		}
+		log.Printf("Error finding test case for prompt %s: %v", prompt.Name, err)
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "No test case found for prompt",
+				Data:    fmt.Sprintf("prompt: %s, args: %v", prompt.Name, args),
+			},
+		}
+	}
+
+	description := testCase.Description
+	if description == "" {
+		description = prompt.Description
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: GetPromptResult{
+			Description: description,
+			Messages:    testCase.Messages,
+		},
+	}
+}
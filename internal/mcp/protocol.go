@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// MCP protocol revisions understood by the server
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
+)
+
+// defaultProtocolVersions is used when tools.yaml does not list supported versions (preferred first)
+var defaultProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// assumedProtocolVersion applies to clients that never negotiated a version, as the
+// Streamable HTTP transport specifies for requests without an MCP-Protocol-Version header
+const assumedProtocolVersion = ProtocolVersion20250326
+
+// protocolVersionHeader is the HTTP header carrying the negotiated version on every request
+const protocolVersionHeader = "MCP-Protocol-Version"
+
+// protocolAtLeast reports whether version is the same as or newer than minimum
+// Protocol versions are dates, so they order lexically
+func protocolAtLeast(version, minimum string) bool {
+	return version >= minimum
+}
+
+// supportedProtocolVersions returns the versions the server accepts, preferred first
+func (s *MockMCPServer) supportedProtocolVersions() []string {
+	if versions := s.toolManager.GetServerConfig().ProtocolVersions; len(versions) > 0 {
+		return versions
+	}
+	return defaultProtocolVersions
+}
+
+// isSupportedProtocolVersion reports whether the server accepts the given version
+func (s *MockMCPServer) isSupportedProtocolVersion(version string) bool {
+	for _, supported := range s.supportedProtocolVersions() {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateProtocolVersion picks the version to use for a client's initialize request
+// A supported version is echoed back; otherwise the preferred version is offered instead,
+// unless strictProtocolVersion is set, in which case an error is returned
+func (s *MockMCPServer) negotiateProtocolVersion(requested string) (string, error) {
+	if s.isSupportedProtocolVersion(requested) {
+		return requested, nil
+	}
+	if s.toolManager.GetServerConfig().StrictProtocolVersion {
+		return "", fmt.Errorf("unsupported protocol version: %s", requested)
+	}
+	return s.supportedProtocolVersions()[0], nil
+}
+
+// checkProtocolVersionHeader validates the MCP-Protocol-Version header of an HTTP request
+// The header must name a supported version, and the negotiated one if the session has one;
+// sessionless requests adopt the header's version for the duration of the request
+func (s *MockMCPServer) checkProtocolVersionHeader(r *http.Request, session *Session) error {
+	version := r.Header.Get(protocolVersionHeader)
+	if version == "" {
+		return nil
+	}
+	if !s.isSupportedProtocolVersion(version) {
+		return fmt.Errorf("unsupported %s: %s", protocolVersionHeader, version)
+	}
+	negotiated := session.negotiatedProtocolVersion()
+	if negotiated == "" {
+		session.setProtocolVersion(version)
+	} else if version != negotiated {
+		return fmt.Errorf("%s %s does not match the negotiated protocol version %s", protocolVersionHeader, version, negotiated)
+	}
+	return nil
+}
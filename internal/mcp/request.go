@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// requestContext carries the per-request state handlers need to talk back to the client
+type requestContext struct {
+	ctx     context.Context
+	session *Session
+
+	// send delivers a server-to-client message tied to this request, on the same
+	// stream as its response where the transport allows it
+	send func(msg interface{}) error
+}
+
+// newRequestContext creates a request context whose messages are queued on the session
+func newRequestContext(ctx context.Context, session *Session) *requestContext {
+	return &requestContext{
+		ctx:     ctx,
+		session: session,
+		send: func(msg interface{}) error {
+			session.Send(msg)
+			return nil
+		},
+	}
+}
+
+// notify sends a JSON-RPC notification to the client
+func (rc *requestContext) notify(method string, params interface{}) {
+	if err := rc.send(&MCPNotification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		log.Printf("Failed to send %s to session %s: %v", method, rc.session.ID(), err)
+	}
+}
+
+// request sends a request to the client and waits for its response
+// It fails if the client answers with an error, does not answer within timeout, or the
+// request being handled is cancelled meanwhile
+func (rc *requestContext) request(method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+	id, responses, done, err := rc.session.awaitResponse()
+	defer done()
+	if err != nil {
+		return nil, fmt.Errorf("cannot send %s: %w", method, err)
+	}
+
+	if err := rc.send(&MCPServerRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	log.Printf("Sent %s request %s to session %s", method, id, rc.session.ID())
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-responses:
+		if resp == nil {
+			return nil, fmt.Errorf("no response to %s: %w", method, rc.session.clientError())
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("client returned error %d for %s: %s", resp.Error.Code, method, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("timed out after %s waiting for the %s response", timeout, method)
+	case <-rc.ctx.Done():
+		return nil, rc.ctx.Err()
+	}
+}
+
+// sendProgress runs a test case's progress script, sending notifications/progress
+// for each step when the client supplied a progress token
+func (rc *requestContext) sendProgress(progressToken interface{}, steps []ProgressStep) error {
+	for _, step := range steps {
+		if err := sleepContext(rc.ctx, step.Delay); err != nil {
+			return err
+		}
+		if progressToken == nil {
+			continue
+		}
+
+		params := map[string]interface{}{
+			"progressToken": progressToken,
+			"progress":      step.Progress,
+		}
+		if step.Total > 0 {
+			params["total"] = step.Total
+		}
+		// The message field was added in 2025-03-26
+		if step.Message != "" && protocolAtLeast(rc.session.ProtocolVersion(), ProtocolVersion20250326) {
+			params["message"] = step.Message
+		}
+		rc.notify("notifications/progress", params)
+	}
+	return nil
+}
+
+// sleepContext waits for the given duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
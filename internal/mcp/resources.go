@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// handleListResources handles the resources/list MCP method
+func (s *MockMCPServer) handleListResources(req *MCPRequest) *MCPResponse {
+	configs := s.toolManager.GetAllResources()
+	start, end, nextCursor, pageErr := s.pageBounds(req, "resources", len(configs))
+	if pageErr != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   pageErr,
+		}
+	}
+
+	resources := make([]Resource, 0, end-start)
+	for _, config := range configs[start:end] {
+		resources = append(resources, Resource{
+			URI:         config.URI,
+			Name:        config.Name,
+			Description: config.Description,
+			MimeType:    config.MimeType,
+		})
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  listResult("resources", resources, nextCursor),
+	}
+}
+
+// handleListResourceTemplates handles the resources/templates/list MCP method
+func (s *MockMCPServer) handleListResourceTemplates(req *MCPRequest) *MCPResponse {
+	configs := s.toolManager.GetAllResourceTemplates()
+	start, end, nextCursor, pageErr := s.pageBounds(req, "resourceTemplates", len(configs))
+	if pageErr != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   pageErr,
+		}
+	}
+
+	templates := make([]ResourceTemplate, 0, end-start)
+	for _, config := range configs[start:end] {
+		templates = append(templates, ResourceTemplate{
+			URITemplate: config.URITemplate,
+			Name:        config.Name,
+			Description: config.Description,
+			MimeType:    config.MimeType,
+		})
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  listResult("resourceTemplates", templates, nextCursor),
+	}
+}
+
+// handleReadResource handles the resources/read MCP method
+func (s *MockMCPServer) handleReadResource(session *Session, req *MCPRequest) *MCPResponse {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		data := "missing uri"
+		if err != nil {
+			data = err.Error()
+		}
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    data,
+			},
+		}
+	}
+
+	contents, err := s.readMockResource(session, params.URI)
+	if err != nil {
+		log.Printf("Error reading resource %s: %v", params.URI, err)
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32002,
+				Message: "Resource not found",
+				Data: map[string]interface{}{
+					"uri":    params.URI,
+					"reason": err.Error(),
+				},
+			},
+		}
+	}
+
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  ReadResourceResult{Contents: contents},
+	}
+}
+
+// readMockResource resolves a URI against the configured resources and templates
+// Test cases named after the resource are tried first, with the URI and any template
+// variables as input; static resources fall back to their inline or file contents
+func (s *MockMCPServer) readMockResource(session *Session, uri string) ([]ResourceContents, error) {
+	resource, isStatic := s.toolManager.GetResource(uri)
+
+	var name, mimeType string
+	var defaultTestCase int
+	args := map[string]interface{}{"uri": uri}
+
+	if isStatic {
+		name, mimeType, defaultTestCase = resource.Name, resource.MimeType, resource.DefaultTestCase
+	} else {
+		template, vars, found := s.findResourceTemplate(uri)
+		if !found {
+			return nil, fmt.Errorf("no resource or resource template matches the URI")
+		}
+		name, mimeType, defaultTestCase = template.Name, template.MimeType, template.DefaultTestCase
+		for key, value := range vars {
+			args[key] = value
+		}
+	}
+
+	if testCase, err := s.testCaseManager.FindMatchingTestCase(name, args, session.Roots(), defaultTestCase, ""); err == nil && len(testCase.Contents) > 0 {
+		contents := make([]ResourceContents, 0, len(testCase.Contents))
+		for _, content := range testCase.Contents {
+			if content.URI == "" {
+				content.URI = uri
+			}
+			if content.MimeType == "" {
+				content.MimeType = mimeType
+			}
+			contents = append(contents, content)
+		}
+		return contents, nil
+	}
+
+	if !isStatic {
+		return nil, fmt.Errorf("no test case found for resource template %s", name)
+	}
+
+	content := ResourceContents{
+		URI:      uri,
+		MimeType: resource.MimeType,
+		Text:     resource.Text,
+		Blob:     resource.Blob,
+	}
+	if resource.File != "" {
+		fileContent, err := loadResourceFile(filepath.Join(s.toolManager.GetConfigDir(), resource.File), resource.MimeType)
+		if err != nil {
+			return nil, err
+		}
+		fileContent.URI = uri
+		content = fileContent
+	}
+
+	return []ResourceContents{content}, nil
+}
+
+// findResourceTemplate returns the first resource template matching the URI and its variables
+func (s *MockMCPServer) findResourceTemplate(uri string) (ResourceTemplateConfig, map[string]string, bool) {
+	for _, template := range s.toolManager.GetAllResourceTemplates() {
+		if vars, ok := matchURITemplate(template.URITemplate, uri); ok {
+			return template, vars, true
+		}
+	}
+	return ResourceTemplateConfig{}, nil, false
+}
+
+// uriTemplateExpression matches a single {var} or {+var} expression in a URI template
+var uriTemplateExpression = regexp.MustCompile(`\{(\+?)([A-Za-z0-9_.]+)\}`)
+
+// matchURITemplate matches a URI against a (level 1/2) RFC 6570 template and extracts its variables
+// {var} matches a single path segment; {+var} may span several
+func matchURITemplate(template, uri string) (map[string]string, bool) {
+	var pattern strings.Builder
+	var names []string
+
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range uriTemplateExpression.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		if loc[3] > loc[2] {
+			pattern.WriteString("(.+)")
+		} else {
+			pattern.WriteString("([^/?#]+)")
+		}
+		names = append(names, template[loc[4]:loc[5]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, false
+	}
+
+	match := re.FindStringSubmatch(uri)
+	if match == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(names))
+	for i, name := range names {
+		vars[name] = match[i+1]
+	}
+	return vars, true
+}
+
+// loadResourceFile reads a file as resource contents
+// Textual MIME types are returned as text, everything else as a base64 blob
+func loadResourceFile(path, mimeType string) (ResourceContents, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ResourceContents{}, fmt.Errorf("failed to read resource file: %w", err)
+	}
+
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(path))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+	}
+
+	content := ResourceContents{MimeType: mimeType}
+	if isTextMimeType(mimeType) {
+		content.Text = string(data)
+	} else {
+		content.Blob = base64.StdEncoding.EncodeToString(data)
+	}
+	return content, nil
+}
+
+// isTextMimeType reports whether a MIME type is textual
+func isTextMimeType(mimeType string) bool {
+	mediaType := strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0])
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	for _, suffix := range []string{"json", "xml", "yaml", "javascript"} {
+		if strings.HasSuffix(mediaType, suffix) {
+			return true
+		}
+	}
+	return false
+}
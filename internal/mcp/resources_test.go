@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchURITemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		want     map[string]string // nil when the URI should not match
+	}{
+		{"one variable", "users://{id}", "users://42", map[string]string{"id": "42"}},
+		{"two variables", "logs://{service}/{date}", "logs://api/2025-01-01", map[string]string{"service": "api", "date": "2025-01-01"}},
+		{"no variables", "config://app/settings", "config://app/settings", map[string]string{}},
+		{"no variables mismatch", "config://app/settings", "config://app/other", nil},
+		{"variable does not span segments", "logs://{service}/{date}", "logs://api/2025/01", nil},
+		{"variable stops at a query", "users://{id}", "users://42?x=1", nil},
+		{"variable stops at a fragment", "users://{id}", "users://42#top", nil},
+		{"empty variable", "users://{id}", "users://", nil},
+		{"reserved variable spans segments", "file:///{+path}", "file:///src/main.go", map[string]string{"path": "src/main.go"}},
+		{"reserved variable with a suffix", "repo://{+path}/blame", "repo://a/b/c/blame", map[string]string{"path": "a/b/c"}},
+		{"empty reserved variable", "file:///{+path}", "file:///", nil},
+		{"variable name with a dot", "db://{table.name}", "db://users", map[string]string{"table.name": "users"}},
+		{"literal dot is not a wildcard", "db://v1.{id}", "db://v1x42", nil},
+		{"literal metacharacters", "q://a+b/(x)/{id}", "q://a+b/(x)/7", map[string]string{"id": "7"}},
+		{"anchored at the start", "users://{id}", "old-users://42", nil},
+		{"anchored at the end", "users://{id}/profile", "users://42/profile/extra", nil},
+		{"unsupported operator is literal", "search://{?q}", "search://{?q}", map[string]string{}},
+		{"unsupported operator does not expand", "search://{?q}", "search://?q=go", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchURITemplate(tt.template, tt.uri)
+			if tt.want == nil {
+				if ok {
+					t.Errorf("matchURITemplate(%q, %q) = %v, want no match", tt.template, tt.uri, got)
+				}
+				return
+			}
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchURITemplate(%q, %q) = %v, %v, want %v", tt.template, tt.uri, got, ok, tt.want)
+			}
+		})
+	}
+}
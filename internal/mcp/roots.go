@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// rootsRequestTimeout is how long to wait for the client to answer roots/list
+const rootsRequestTimeout = 30 * time.Second
+
+// Root is a workspace root exposed by the client
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// refreshRoots asks the client for its roots and records them on the session
+// It does nothing for clients that did not declare the roots capability
+func (s *MockMCPServer) refreshRoots(session *Session) {
+	if !session.hasClientCapability("roots") {
+		return
+	}
+
+	rc := newRequestContext(session.ctx, session)
+	raw, err := rc.request("roots/list", nil, rootsRequestTimeout)
+	if err != nil {
+		log.Printf("roots/list on session %s failed: %v", session.ID(), err)
+		return
+	}
+
+	var result struct {
+		Roots []Root `json:"roots"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		log.Printf("Invalid roots/list result on session %s: %v", session.ID(), err)
+		return
+	}
+
+	session.setRoots(result.Roots)
+	log.Printf("Session %s has %d root(s): %v", session.ID(), len(result.Roots), result.Roots)
+
+	s.journal.Record(JournalEntry{
+		SessionID: session.ID(),
+		Method:    "roots/list",
+		Status:    "ok",
+		Roots:     result.Roots,
+	})
+}
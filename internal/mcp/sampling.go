@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultSamplingTimeout is how long to wait for a sampling reply when the test case sets no timeout
+const defaultSamplingTimeout = 60 * time.Second
+
+// defaultSamplingMaxTokens is sent when the test case does not set maxTokens, which the protocol requires
+const defaultSamplingMaxTokens = 1000
+
+// samplingResult is the client's reply to sampling/createMessage
+type samplingResult struct {
+	Role       string       `json:"role"`
+	Content    ContentBlock `json:"content"`
+	Model      string       `json:"model"`
+	StopReason string       `json:"stopReason,omitempty"`
+}
+
+// runSampling asks the client for an LLM completion as scripted by a test case's sampling step
+// It returns the tool result chosen by the reply and the {{sampling.*}} values to fill into it
+// Failures on the client side become error results; an error is only returned if the call is cancelled
+func (s *MockMCPServer) runSampling(rc *requestContext, toolName string, testCase *TestCaseConfig) (ToolResult, map[string]string, error) {
+	step := testCase.Sampling
+
+	if !rc.session.hasClientCapability("sampling") {
+		log.Printf("Client on session %s does not support sampling, failing tool %s", rc.session.ID(), toolName)
+		return errorResult(fmt.Sprintf("Tool %s needs sampling, which the client did not declare", toolName)), nil, nil
+	}
+
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = defaultSamplingTimeout
+	}
+
+	raw, err := rc.request("sampling/createMessage", samplingParams(step), timeout)
+	if err != nil {
+		if rc.ctx.Err() != nil {
+			return ToolResult{}, nil, err
+		}
+		log.Printf("Sampling for tool %s failed: %v", toolName, err)
+		return errorResult(fmt.Sprintf("Sampling failed: %v", err)), nil, nil
+	}
+
+	var reply samplingResult
+	if err := json.Unmarshal(raw, &reply); err != nil {
+		return errorResult(fmt.Sprintf("Invalid sampling result: %v", err)), nil, nil
+	}
+	log.Printf("Sampling reply for tool %s from model %s: %q", toolName, reply.Model, reply.Content.Text)
+
+	result := testCase.Response
+	for i, candidate := range step.Replies {
+		if candidate.matches(reply) {
+			log.Printf("Sampling reply matched reply %d of the test case", i+1)
+			result = candidate.Response
+			break
+		}
+	}
+
+	return result, map[string]string{
+		"sampling.text":       reply.Content.Text,
+		"sampling.role":       reply.Role,
+		"sampling.model":      reply.Model,
+		"sampling.stopReason": reply.StopReason,
+	}, nil
+}
+
+// samplingParams builds the sampling/createMessage params of a sampling step
+func samplingParams(step *SamplingStep) map[string]interface{} {
+	maxTokens := step.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultSamplingMaxTokens
+	}
+
+	params := map[string]interface{}{
+		"messages":  step.Messages,
+		"maxTokens": maxTokens,
+	}
+	if step.SystemPrompt != "" {
+		params["systemPrompt"] = step.SystemPrompt
+	}
+	if step.IncludeContext != "" {
+		params["includeContext"] = step.IncludeContext
+	}
+	if step.Temperature != nil {
+		params["temperature"] = *step.Temperature
+	}
+	if len(step.StopSequences) > 0 {
+		params["stopSequences"] = step.StopSequences
+	}
+	if len(step.ModelPreferences) > 0 {
+		params["modelPreferences"] = step.ModelPreferences
+	}
+	if len(step.Metadata) > 0 {
+		params["metadata"] = step.Metadata
+	}
+	return params
+}
+
+// matches reports whether a sampling reply satisfies every condition of the entry
+func (r SamplingReply) matches(reply samplingResult) bool {
+	text := reply.Content.Text
+	if r.Text != "" && text != r.Text {
+		return false
+	}
+	if r.Contains != "" && !strings.Contains(text, r.Contains) {
+		return false
+	}
+	if r.Pattern != "" {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			log.Printf("Invalid sampling reply pattern %q: %v", r.Pattern, err)
+			return false
+		}
+		if !re.MatchString(text) {
+			return false
+		}
+	}
+	if r.Model != "" && reply.Model != r.Model {
+		return false
+	}
+	return true
+}
+
+// interpolateResult replaces {{name}} placeholders in the text of a result's content blocks
+func interpolateResult(result ToolResult, vars map[string]string) ToolResult {
+	replacements := make([]string, 0, len(vars)*2)
+	for name, value := range vars {
+		replacements = append(replacements, "{{"+name+"}}", value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	content := make([]ContentBlock, len(result.Content))
+	for i, block := range result.Content {
+		block.Text = replacer.Replace(block.Text)
+		content[i] = block
+	}
+	result.Content = content
+	return result
+}
+
+// errorResult creates a tool result reporting an error as text
+func errorResult(text string) ToolResult {
+	return ToolResult{
+		Content: []ContentBlock{
+			{
+				Type: "text",
+				Text: text,
+			},
+		},
+		IsError: true,
+	}
+}
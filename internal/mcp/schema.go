@@ -0,0 +1,276 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validateSchema checks a value against a JSON Schema
+// It covers the subset of JSON Schema used by tool schemas: type, enum, const, properties,
+// required, additionalProperties, items, numeric and length bounds, pattern, anyOf, oneOf and allOf
+func validateSchema(schema map[string]interface{}, value interface{}) error {
+	normalizedSchema, err := normalizeJSON(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	normalizedValue, err := normalizeJSON(value)
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	return validateSchemaValue(normalizedSchema, normalizedValue, "$")
+}
+
+// normalizeJSON round-trips a value through JSON so YAML-decoded values (ints, typed slices)
+// compare the same way as values decoded from a JSON-RPC message
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// validateSchemaValue validates a normalized value against a normalized schema at the given path
+func validateSchemaValue(schemaValue interface{}, value interface{}, path string) error {
+	// Boolean schemas: true accepts everything, false nothing
+	if accept, ok := schemaValue.(bool); ok {
+		if !accept {
+			return fmt.Errorf("%s: no value is allowed here", path)
+		}
+		return nil
+	}
+	schema, ok := schemaValue.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if types, ok := schema["type"]; ok && !matchesSchemaType(types, value) {
+		return fmt.Errorf("%s: expected %s, got %s", path, describeSchemaType(types), jsonTypeName(value))
+	}
+
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		return fmt.Errorf("%s: must be %v", path, constant)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if reflect.DeepEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := validateSchemaObject(schema, v, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := validateSchemaArray(schema, v, path); err != nil {
+			return err
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			return fmt.Errorf("%s: %v is less than the minimum %v", path, v, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			return fmt.Errorf("%s: %v is greater than the maximum %v", path, v, maximum)
+		}
+		if minimum, ok := schema["exclusiveMinimum"].(float64); ok && v <= minimum {
+			return fmt.Errorf("%s: %v must be greater than %v", path, v, minimum)
+		}
+		if maximum, ok := schema["exclusiveMaximum"].(float64); ok && v >= maximum {
+			return fmt.Errorf("%s: %v must be less than %v", path, v, maximum)
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+			return fmt.Errorf("%s: shorter than %v characters", path, minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+			return fmt.Errorf("%s: longer than %v characters", path, maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern %q: %w", path, pattern, err)
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s: %q does not match pattern %q", path, v, pattern)
+			}
+		}
+	}
+
+	return validateSchemaCombinators(schema, value, path)
+}
+
+// validateSchemaObject applies the object keywords of a schema
+func validateSchemaObject(schema map[string]interface{}, value map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, exists := value[key]; !exists {
+				return fmt.Errorf("%s: missing required property %q", path, key)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Validate in key order so the reported error is deterministic
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propertyPath := path + "." + key
+		if propertySchema, ok := properties[key]; ok {
+			if err := validateSchemaValue(propertySchema, value[key], propertyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if additional, ok := schema["additionalProperties"]; ok {
+			if allowed, isBool := additional.(bool); isBool && !allowed {
+				return fmt.Errorf("%s: unexpected property %q", path, key)
+			}
+			if err := validateSchemaValue(additional, value[key], propertyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchemaArray applies the array keywords of a schema
+func validateSchemaArray(schema map[string]interface{}, value []interface{}, path string) error {
+	length := float64(len(value))
+	if minItems, ok := schema["minItems"].(float64); ok && length < minItems {
+		return fmt.Errorf("%s: fewer than %v items", path, minItems)
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && length > maxItems {
+		return fmt.Errorf("%s: more than %v items", path, maxItems)
+	}
+	if items, ok := schema["items"]; ok {
+		for i, item := range value {
+			if err := validateSchemaValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchemaCombinators applies allOf, anyOf and oneOf
+func validateSchemaCombinators(schema map[string]interface{}, value interface{}, path string) error {
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			if err := validateSchemaValue(sub, value, path); err != nil {
+				return err
+			}
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if validateSchemaValue(sub, value, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: does not match any schema in anyOf", path)
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range oneOf {
+			if validateSchemaValue(sub, value, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: matches %d schemas in oneOf, expected exactly 1", path, matches)
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a value has the type (or one of the types) a schema allows
+func matchesSchemaType(types interface{}, value interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		return matchesJSONType(t, value)
+	case []interface{}:
+		for _, option := range t {
+			if name, ok := option.(string); ok && matchesJSONType(name, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// matchesJSONType reports whether a value is of the named JSON Schema type
+func matchesJSONType(name string, value interface{}) bool {
+	switch name {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == name
+	}
+}
+
+// jsonTypeName returns the JSON Schema type name of a normalized value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// describeSchemaType formats a schema's type keyword for error messages
+func describeSchemaType(types interface{}) string {
+	if list, ok := types.([]interface{}); ok {
+		names := make([]string, 0, len(list))
+		for _, option := range list {
+			names = append(names, fmt.Sprint(option))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
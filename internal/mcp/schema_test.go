@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseSchema decodes a schema the way outputSchema and requestedSchema are decoded from YAML
+func parseSchema(t *testing.T, schema string) map[string]interface{} {
+	t.Helper()
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal([]byte(schema), &parsed); err != nil {
+		t.Fatalf("invalid schema YAML %q: %v", schema, err)
+	}
+	return parsed
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string      // as YAML
+		value   interface{} // Go value, as decoded from YAML or JSON
+		wantErr string      // substring of the expected error, "" for a valid value
+	}{
+		{"string", `{type: string}`, "x", ""},
+		{"string got number", `{type: string}`, 1.0, "expected string, got number"},
+		{"integer", `{type: integer}`, 3, ""},
+		{"integer as float", `{type: integer}`, 3.0, ""},
+		{"integer with a fraction", `{type: integer}`, 3.5, "expected integer"},
+		{"number from a YAML int", `{type: number}`, 7, ""},
+		{"boolean", `{type: boolean}`, true, ""},
+		{"null", `{type: "null"}`, nil, ""},
+		{"type list", `{type: [string, "null"]}`, nil, ""},
+		{"type list mismatch", `{type: [string, "null"]}`, 1.0, "expected string or null"},
+		{"array got object", `{type: array}`, map[string]interface{}{}, "expected array, got object"},
+
+		{"enum", `{enum: [red, green]}`, "green", ""},
+		{"enum mismatch", `{enum: [red, green]}`, "blue", "is not one of"},
+		{"enum of numbers", `{enum: [1, 2]}`, 2.0, ""},
+		{"const", `{const: 42}`, 42, ""},
+		{"const mismatch", `{const: 42}`, 41, "must be 42"},
+
+		{"required", `{type: object, required: [a, b]}`, map[string]interface{}{"a": 1, "b": 2}, ""},
+		{"required missing", `{type: object, required: [a, b]}`, map[string]interface{}{"a": 1}, `missing required property "b"`},
+		{"property type", `{properties: {n: {type: number}}}`, map[string]interface{}{"n": "x"}, "$.n: expected number"},
+		{"nested property", `{properties: {o: {properties: {id: {type: string}}}}}`, map[string]interface{}{"o": map[string]interface{}{"id": 1}}, "$.o.id"},
+		{"additional properties allowed", `{properties: {a: {}}}`, map[string]interface{}{"b": 1}, ""},
+		{"additional properties false", `{properties: {a: {}}, additionalProperties: false}`, map[string]interface{}{"a": 1, "b": 1}, `unexpected property "b"`},
+		{"additional properties schema", `{additionalProperties: {type: string}}`, map[string]interface{}{"b": 1}, "$.b: expected string"},
+
+		{"items", `{items: {type: integer}}`, []interface{}{1, 2}, ""},
+		{"items mismatch", `{items: {type: integer}}`, []interface{}{1, "x"}, "$[1]: expected integer"},
+		{"min items", `{minItems: 2}`, []interface{}{1}, "fewer than 2 items"},
+		{"max items", `{maxItems: 1}`, []interface{}{1, 2}, "more than 1 items"},
+
+		{"minimum", `{minimum: 0}`, -1, "less than the minimum"},
+		{"maximum", `{maximum: 10}`, 10, ""},
+		{"exclusive minimum", `{exclusiveMinimum: 0}`, 0, "must be greater than 0"},
+		{"exclusive maximum", `{exclusiveMaximum: 10}`, 10, "must be less than 10"},
+		{"min length counts characters", `{minLength: 2}`, "é", "shorter than 2 characters"},
+		{"max length", `{maxLength: 3}`, "abcd", "longer than 3 characters"},
+		{"pattern", `{pattern: "^[a-z]+$"}`, "abc", ""},
+		{"pattern mismatch", `{pattern: "^[a-z]+$"}`, "ABC", "does not match pattern"},
+		{"invalid pattern", `{pattern: "([a-"}`, "a", "invalid pattern"},
+
+		{"all of", `{allOf: [{type: number}, {minimum: 5}]}`, 3, "less than the minimum"},
+		{"any of", `{anyOf: [{type: string}, {type: number}]}`, 3, ""},
+		{"any of mismatch", `{anyOf: [{type: string}, {type: number}]}`, true, "does not match any schema in anyOf"},
+		{"one of", `{oneOf: [{type: string}, {type: number}]}`, "x", ""},
+		{"one of matching both", `{oneOf: [{type: number}, {minimum: 0}]}`, 3, "matches 2 schemas in oneOf"},
+		{"false schema", `{properties: {a: false}}`, map[string]interface{}{"a": 1}, "no value is allowed here"},
+		{"empty schema", `{}`, map[string]interface{}{"anything": []interface{}{1}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchema(parseSchema(t, tt.schema), tt.value)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("schema %s rejected %v: %v", tt.schema, tt.value, err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("schema %s accepted %v, want error containing %q", tt.schema, tt.value, tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("schema %s with %v: error %q does not contain %q", tt.schema, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckStructuredContent(t *testing.T) {
+	schema := parseSchema(t, `{type: object, required: [temperature], properties: {temperature: {type: number}}}`)
+
+	tests := []struct {
+		name    string
+		result  ToolResult
+		wantErr bool
+	}{
+		{"valid", ToolResult{StructuredContent: map[string]interface{}{"temperature": 21.5}}, false},
+		{"missing", ToolResult{}, true},
+		{"invalid", ToolResult{StructuredContent: map[string]interface{}{"temperature": "warm"}}, true},
+		{"error results are exempt", ToolResult{IsError: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkStructuredContent(schema, tt.result); (err != nil) != tt.wantErr {
+				t.Errorf("checkStructuredContent() error = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"time"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/gorilla/websocket"
 )
@@ -17,6 +19,8 @@ import (
 type MockMCPServer struct {
 	toolManager     *ToolManager
 	testCaseManager *TestCaseManager
+	sessions        *SessionManager
+	journal         *RequestJournal
 	upgrader        websocket.Upgrader
 	webhookHandler  *WebhookHandler
 }
@@ -48,6 +52,8 @@ func NewMockMCPServerWithWebhook(configPath, testcasesDir string, githubSync *Gi
 	server := &MockMCPServer{
 		toolManager:     toolManager,
 		testCaseManager: testCaseManager,
+		sessions:        NewSessionManager(),
+		journal:         NewRequestJournal(),
 		webhookHandler:  webhookHandler,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
@@ -56,9 +62,92 @@ func NewMockMCPServerWithWebhook(configPath, testcasesDir string, githubSync *Gi
 		},
 	}
 
+	// Tell connected clients whenever the lists they may have cached change
+	// A webhook sync rewrites the watched files, so it is covered by the reloads too
+	toolManager.SetReloadCallback(server.onConfigChanged)
+	testCaseManager.SetReloadCallback(server.validateTestCases)
+
+	server.validateTestCases()
+
 	return server, nil
 }
 
+// onConfigChanged re-validates test cases and notifies clients after the config or test cases change
+func (s *MockMCPServer) onConfigChanged() {
+	s.validateTestCases()
+	s.notifyListChanged()
+}
+
+// validateTestCases logs every test case whose structuredContent does not conform to its tool's
+// outputSchema, and every test case name that more than one tool, prompt, resource or completion uses
+func (s *MockMCPServer) validateTestCases() {
+	for _, problem := range s.testCaseManager.ValidateStructuredContent(s.toolManager.GetAllTools()) {
+		log.Printf("Warning: invalid test case %s", problem)
+	}
+	for _, collision := range s.testCaseNameCollisions() {
+		log.Printf("Warning: %s", collision)
+	}
+}
+
+// testCaseNameCollisions describes each name that test cases are filed under and that more than
+// one tool, prompt, resource or completion looks its test cases up by
+// Test case files carry only the name, so each of them is served the same test cases
+func (s *MockMCPServer) testCaseNameCollisions() []string {
+	owners := make(map[string][]string)
+	for _, tool := range s.toolManager.GetAllTools() {
+		owners[tool.Name] = append(owners[tool.Name], "tool "+tool.Name)
+	}
+	for _, prompt := range s.toolManager.GetAllPrompts() {
+		owners[prompt.Name] = append(owners[prompt.Name], "prompt "+prompt.Name)
+	}
+	for _, resource := range s.toolManager.GetAllResources() {
+		owners[resource.Name] = append(owners[resource.Name], "resource "+resource.URI)
+	}
+	for _, resourceTemplate := range s.toolManager.GetAllResourceTemplates() {
+		owners[resourceTemplate.Name] = append(owners[resourceTemplate.Name], "resource template "+resourceTemplate.URITemplate)
+	}
+	for _, completion := range s.toolManager.GetAllCompletions() {
+		if completion.Mode != CompletionModeTestCase {
+			continue
+		}
+		refName, ref := completion.Prompt, "prompt "+completion.Prompt
+		if completion.ResourceTemplate != "" {
+			resourceTemplate, _ := s.toolManager.GetResourceTemplate(completion.ResourceTemplate)
+			refName, ref = resourceTemplate.Name, completion.ResourceTemplate
+		}
+		name := fmt.Sprintf("%s-%s-completion", refName, completion.Argument)
+		owners[name] = append(owners[name], fmt.Sprintf("completion of %s argument %s", ref, completion.Argument))
+	}
+
+	var collisions []string
+	for name, users := range owners {
+		if len(users) > 1 && len(s.testCaseManager.testCasesFor(name)) > 0 {
+			collisions = append(collisions, fmt.Sprintf("test cases for %q are shared by %s", name, strings.Join(users, ", ")))
+		}
+	}
+	sort.Strings(collisions)
+	return collisions
+}
+
+// notifyListChanged sends list_changed notifications to every active session
+func (s *MockMCPServer) notifyListChanged() {
+	methods := []string{"notifications/tools/list_changed"}
+	if s.toolManager.HasResources() {
+		methods = append(methods, "notifications/resources/list_changed")
+	}
+	if s.toolManager.HasPrompts() {
+		methods = append(methods, "notifications/prompts/list_changed")
+	}
+
+	for _, method := range methods {
+		s.sessions.Broadcast(&MCPNotification{
+			JSONRPC: "2.0",
+			Method:  method,
+		})
+	}
+	log.Printf("Sent list_changed notifications to active sessions")
+}
+
 // HandleWebhook handles GitHub webhook requests
 func (s *MockMCPServer) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	if s.webhookHandler == nil {
@@ -70,116 +159,233 @@ func (s *MockMCPServer) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 
 // Close closes the server and cleans up resources
 func (s *MockMCPServer) Close() error {
+	s.sessions.CloseAll()
+	s.testCaseManager.Close()
 	return s.toolManager.Close()
 }
 
-// HandleRequest handles incoming HTTP requests
+// HandleRequest handles incoming requests on the MCP endpoint
+// POST carries client messages, GET opens the session's SSE stream (or upgrades to WebSocket)
+// and DELETE ends a session, as described by the Streamable HTTP transport
 func (s *MockMCPServer) HandleRequest(w http.ResponseWriter, r *http.Request) {
-	if r.Method == "POST" {
-		s.handleHTTPRequest(w, r)
-	} else if r.Header.Get("Upgrade") == "websocket" {
+	switch {
+	case websocket.IsWebSocketUpgrade(r):
 		s.handleWebSocketRequest(w, r)
-	} else {
+	case r.Method == http.MethodPost:
+		s.handleHTTPRequest(w, r)
+	case r.Method == http.MethodGet:
+		s.handleSessionStream(w, r)
+	case r.Method == http.MethodDelete:
+		s.handleSessionDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-// handleHTTPRequest handles standard HTTP POST requests
-func (s *MockMCPServer) handleHTTPRequest(w http.ResponseWriter, r *http.Request) {
-	var req MCPRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.sendError(w, nil, -32700, "Parse error", err.Error())
+// handleWebSocketRequest handles WebSocket connections
+func (s *MockMCPServer) handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
+	defer conn.Close()
 
-	// Check if streaming is requested
-	if r.Header.Get("Accept") == "text/event-stream" || r.URL.Query().Get("stream") == "true" {
-		s.handleStreamingRequest(w, r, &req)
-		return
-	}
+	session := newSession("websocket")
+	s.sessions.Add(session)
+	defer s.sessions.Remove(session.ID())
+
+	// gorilla/websocket allows only one concurrent writer
+	var writeMutex sync.Mutex
+	writeJSON := func(msg interface{}) error {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		return conn.WriteJSON(msg)
+	}
+
+	// Deliver server-initiated messages queued on the session
+	go func() {
+		for {
+			select {
+			case msg := <-session.outbound:
+				if err := writeJSON(msg); err != nil {
+					log.Printf("WebSocket write error: %v", err)
+				}
+			case <-session.ctx.Done():
+				return
+			}
+		}
+	}()
 
-	response := s.processRequest(&req)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			log.Printf("WebSocket read error: %v", err)
+			break
+		}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+		// Requests run concurrently so that slow calls can be cancelled
+		go func(data []byte) {
+			rc := &requestContext{ctx: session.ctx, session: session, send: writeJSON}
+			reply := s.processMessage(rc, data)
+			if reply == nil {
+				return
+			}
+			if err := writeJSON(reply); err != nil {
+				log.Printf("WebSocket write error: %v", err)
+			}
+		}(data)
+	}
 }
 
-// handleStreamingRequest handles Server-Sent Events streaming requests
-func (s *MockMCPServer) handleStreamingRequest(w http.ResponseWriter, r *http.Request, req *MCPRequest) {
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+// processRequest processes MCP protocol requests
+// It returns nil when no response must be sent, e.g. when the request was cancelled
+func (s *MockMCPServer) processRequest(rc *requestContext, req *MCPRequest) *MCPResponse {
+	// Responses to server-initiated requests are routed to the request awaiting them
+	if req.isResponse() {
+		s.handleClientResponse(rc.session, req)
+		return nil
+	}
+
+	// Notifications (no ID) never get a response, not even an error for an unknown method
+	if req.ID == nil {
+		s.handleNotification(rc.session, req)
+		return nil
+	}
+
+	// Track the request so that notifications/cancelled can abort it
+	ctx, done := rc.session.trackRequest(rc.ctx, req.ID)
+	defer done()
+	rc = &requestContext{ctx: ctx, session: rc.session, send: rc.send}
+
+	response := s.dispatchRequest(rc, req)
+
+	entry := newJournalEntry(rc.session, req)
+	switch {
+	case rc.ctx.Err() != nil:
+		log.Printf("Request %v (%s) on session %s was cancelled, not sending a response", req.ID, req.Method, rc.session.ID())
+		entry.Status = "cancelled"
+		response = nil
+	case response.Error != nil:
+		entry.Status = "error"
+		entry.Error = response.Error.Message
+	default:
+		entry.Status = "ok"
+	}
+	s.journal.Record(entry)
+
+	return response
+}
 
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+// handleNotification handles a JSON-RPC notification sent by the client
+func (s *MockMCPServer) handleNotification(session *Session, req *MCPRequest) {
+	switch req.Method {
+	case "notifications/cancelled":
+		s.handleCancelled(session, req)
 		return
+	case "notifications/initialized":
+		log.Printf("Session %s initialized", session.ID())
+		go s.refreshRoots(session)
+	case "notifications/roots/list_changed":
+		log.Printf("Roots changed on session %s", session.ID())
+		go s.refreshRoots(session)
+	default:
+		log.Printf("Ignoring notification %s on session %s", req.Method, session.ID())
 	}
 
-	// Send initial response
-	response := s.processRequest(req)
-	data, _ := json.Marshal(response)
-	fmt.Fprintf(w, "data: %s\n\n", data)
-	flusher.Flush()
+	entry := newJournalEntry(session, req)
+	entry.Status = "received"
+	s.journal.Record(entry)
+}
 
-	// If it's a tool call, stream progress updates
-	if req.Method == "tools/call" {
-		var toolCall ToolCall
-		if err := json.Unmarshal(req.Params, &toolCall); err == nil {
-			s.streamToolCallProgress(w, flusher, &toolCall)
-		}
+// handleClientResponse delivers the client's response to a server-initiated request
+func (s *MockMCPServer) handleClientResponse(session *Session, resp *MCPRequest) {
+	entry := newJournalEntry(session, resp)
+	entry.Method = "response"
+	entry.Status = "received"
+	if resp.Error != nil {
+		entry.Status = "error"
+		entry.Error = resp.Error.Message
 	}
-}
+	s.journal.Record(entry)
 
-// streamToolCallProgress sends progress updates for tool calls
-func (s *MockMCPServer) streamToolCallProgress(w http.ResponseWriter, flusher http.Flusher, toolCall *ToolCall) {
-	// Send progress updates
-	for i := 0; i < 3; i++ {
-		time.Sleep(500 * time.Millisecond)
-		progress := map[string]interface{}{
-			"type":    "progress",
-			"message": fmt.Sprintf("Processing %s... (%d/3)", toolCall.Name, i+1),
-		}
-		data, _ := json.Marshal(progress)
-		fmt.Fprintf(w, "data: %s\n\n", data)
-		flusher.Flush()
+	if !session.deliverResponse(resp) {
+		log.Printf("Ignoring response %v on session %s: no request is awaiting it", resp.ID, session.ID())
 	}
 }
 
-// handleWebSocketRequest handles WebSocket connections
-func (s *MockMCPServer) handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
-	conn, err := s.upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+// handleCancelled handles notifications/cancelled by aborting the referenced in-flight request
+func (s *MockMCPServer) handleCancelled(session *Session, req *MCPRequest) {
+	var params struct {
+		RequestID interface{} `json:"requestId"`
+		Reason    string      `json:"reason,omitempty"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		log.Printf("Invalid notifications/cancelled params: %v", err)
 		return
 	}
-	defer conn.Close()
 
-	for {
-		var req MCPRequest
-		if err := conn.ReadJSON(&req); err != nil {
-			log.Printf("WebSocket read error: %v", err)
-			break
-		}
+	entry := newJournalEntry(session, req)
+	entry.RequestID = params.RequestID
+	entry.Reason = params.Reason
+	if session.cancelRequest(params.RequestID) {
+		log.Printf("Cancelled request %v on session %s (reason: %s)", params.RequestID, session.ID(), params.Reason)
+		entry.Status = "cancelled"
+	} else {
+		// The request may already have completed; the notification is still recorded
+		log.Printf("Cancellation for unknown or completed request %v on session %s", params.RequestID, session.ID())
+		entry.Status = "received"
+	}
+	s.journal.Record(entry)
+}
 
-		response := s.processRequest(&req)
-		if err := conn.WriteJSON(response); err != nil {
-			log.Printf("WebSocket write error: %v", err)
-			break
+// newJournalEntry creates a journal entry describing a request
+func newJournalEntry(session *Session, req *MCPRequest) JournalEntry {
+	entry := JournalEntry{
+		SessionID: session.ID(),
+		RequestID: req.ID,
+		Method:    req.Method,
+	}
+	if req.Method == "tools/call" {
+		var toolCall ToolCall
+		if err := json.Unmarshal(req.Params, &toolCall); err == nil {
+			entry.Tool = toolCall.Name
+			entry.Arguments = toolCall.Arguments
 		}
 	}
+	return entry
 }
 
-// processRequest processes MCP protocol requests
-func (s *MockMCPServer) processRequest(req *MCPRequest) *MCPResponse {
+// dispatchRequest routes a request to the handler for its method
+func (s *MockMCPServer) dispatchRequest(rc *requestContext, req *MCPRequest) *MCPResponse {
 	switch req.Method {
 	case "initialize":
-		return s.handleInitialize(req)
+		return s.handleInitialize(rc.session, req)
+	case "ping":
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]interface{}{},
+		}
+	case "completion/complete":
+		return s.handleComplete(rc.session, req)
+	case "logging/setLevel":
+		return s.handleSetLogLevel(rc.session, req)
 	case "tools/list":
-		return s.handleListTools(req)
+		return s.handleListTools(rc.session, req)
 	case "tools/call":
-		return s.handleCallTool(req)
+		return s.handleCallTool(rc, req)
+	case "resources/list":
+		return s.handleListResources(req)
+	case "resources/templates/list":
+		return s.handleListResourceTemplates(req)
+	case "resources/read":
+		return s.handleReadResource(rc.session, req)
+	case "prompts/list":
+		return s.handleListPrompts(req)
+	case "prompts/get":
+		return s.handleGetPrompt(rc.session, req)
 	default:
 		return &MCPResponse{
 			JSONRPC: "2.0",
@@ -193,7 +399,7 @@ func (s *MockMCPServer) processRequest(req *MCPRequest) *MCPResponse {
 }
 
 // handleInitialize handles the initialize MCP method
-func (s *MockMCPServer) handleInitialize(req *MCPRequest) *MCPResponse {
+func (s *MockMCPServer) handleInitialize(session *Session, req *MCPRequest) *MCPResponse {
 	var params InitializeParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		return &MCPResponse{
@@ -207,16 +413,33 @@ func (s *MockMCPServer) handleInitialize(req *MCPRequest) *MCPResponse {
 		}
 	}
 
+	version, err := s.negotiateProtocolVersion(params.ProtocolVersion)
+	if err != nil {
+		log.Printf("Rejecting initialize from client %v: %v", params.ClientInfo["name"], err)
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Unsupported protocol version",
+				Data: map[string]interface{}{
+					"supported": s.supportedProtocolVersions(),
+					"requested": params.ProtocolVersion,
+				},
+			},
+		}
+	}
+	session.setProtocolVersion(version)
+	session.setClientCapabilities(params.Capabilities)
+
+	log.Printf("Initialize from client %v on %s session %s (requested protocol %s, using %s)", params.ClientInfo["name"], session.Transport(), session.ID(), params.ProtocolVersion, version)
+
 	return &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
 		Result: InitializeResult{
-			ProtocolVersion: "2024-11-05",
-			Capabilities: map[string]interface{}{
-				"tools": map[string]interface{}{
-					"listChanged": true,
-				},
-			},
+			ProtocolVersion: version,
+			Capabilities:    s.serverCapabilities(),
 			ServerInfo: map[string]interface{}{
 				"name":    "mock-mcp-server",
 				"version": "1.0.0",
@@ -225,24 +448,75 @@ func (s *MockMCPServer) handleInitialize(req *MCPRequest) *MCPResponse {
 	}
 }
 
+// serverCapabilities returns the capabilities advertised in the initialize result
+func (s *MockMCPServer) serverCapabilities() map[string]interface{} {
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{
+			"listChanged": true,
+		},
+		"logging": map[string]interface{}{},
+	}
+
+	if s.toolManager.HasResources() {
+		capabilities["resources"] = map[string]interface{}{
+			"listChanged": true,
+		}
+	}
+
+	if s.toolManager.HasPrompts() {
+		capabilities["prompts"] = map[string]interface{}{
+			"listChanged": true,
+		}
+	}
+
+	if s.toolManager.HasCompletions() {
+		capabilities["completions"] = map[string]interface{}{}
+	}
+
+	return capabilities
+}
+
 // handleListTools handles the tools/list MCP method
-func (s *MockMCPServer) handleListTools(req *MCPRequest) *MCPResponse {
+func (s *MockMCPServer) handleListTools(session *Session, req *MCPRequest) *MCPResponse {
 	tools := s.toolManager.GetAllTools()
+	start, end, nextCursor, pageErr := s.pageBounds(req, "tools", len(tools))
+	if pageErr != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   pageErr,
+		}
+	}
+	tools = tools[start:end]
+
+	// Leave out fields the session's protocol version does not know about
+	version := session.ProtocolVersion()
+	for i := range tools {
+		if !protocolAtLeast(version, ProtocolVersion20250618) {
+			tools[i].Title = ""
+			tools[i].OutputSchema = nil
+			tools[i].Meta = nil
+		}
+		if !protocolAtLeast(version, ProtocolVersion20250326) {
+			tools[i].Annotations = nil
+		}
+	}
 
 	return &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result: map[string]interface{}{
-			"tools": tools,
-		},
+		Result:  listResult("tools", tools, nextCursor),
 	}
 }
 
 // handleCallTool handles the tools/call MCP method
-func (s *MockMCPServer) handleCallTool(req *MCPRequest) *MCPResponse {
+func (s *MockMCPServer) handleCallTool(rc *requestContext, req *MCPRequest) *MCPResponse {
 	var toolCall struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments,omitempty"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken,omitempty"`
+		} `json:"_meta,omitempty"`
 	}
 
 	if err := json.Unmarshal(req.Params, &toolCall); err != nil {
@@ -270,7 +544,18 @@ func (s *MockMCPServer) handleCallTool(req *MCPRequest) *MCPResponse {
 	}
 
 	// Execute mock tool using test cases
-	result := s.executeMockTool(toolCall.Name, toolCall.Arguments)
+	result, err := s.executeMockTool(rc, toolCall.Name, toolCall.Arguments, toolCall.Meta.ProgressToken)
+	if err != nil {
+		return &MCPResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Request interrupted",
+				Data:    err.Error(),
+			},
+		}
+	}
 
 	return &MCPResponse{
 		JSONRPC: "2.0",
@@ -280,16 +565,20 @@ func (s *MockMCPServer) handleCallTool(req *MCPRequest) *MCPResponse {
 }
 
 // executeMockTool executes a tool by finding and returning a matching test case
-func (s *MockMCPServer) executeMockTool(name string, args map[string]interface{}) ToolResult {
+// Any progress script and delay in the test case are played out before the result is returned,
+// and an error is returned if the request is cancelled meanwhile
+func (s *MockMCPServer) executeMockTool(rc *requestContext, name string, args map[string]interface{}, progressToken interface{}) (ToolResult, error) {
 	// Get tool configuration to check default test case setting
 	tool, exists := s.toolManager.GetTool(name)
 	defaultTestCase := 0
+	matchMode := ""
 	if exists {
 		defaultTestCase = tool.DefaultTestCase
+		matchMode = tool.Match
 	}
 
 	// Look for matching test case files
-	testCase, err := s.testCaseManager.FindMatchingTestCase(name, args, defaultTestCase)
+	testCase, err := s.testCaseManager.FindMatchingTestCase(name, args, rc.session.Roots(), defaultTestCase, matchMode)
 	if err != nil {
 		log.Printf("Error finding test case for tool %s: %v", name, err)
 		// Return a default response if no test case found
@@ -301,10 +590,78 @@ func (s *MockMCPServer) executeMockTool(name string, args map[string]interface{}
 				},
 			},
 			IsError: true,
+		}, nil
+	}
+
+	if err := rc.sendLogMessages(testCase.Logs); err != nil {
+		return ToolResult{}, err
+	}
+	if err := rc.sendProgress(progressToken, testCase.Progress); err != nil {
+		return ToolResult{}, err
+	}
+
+	// Elicitation runs first; sampling only follows if the user accepted
+	result := testCase.Response
+	vars := map[string]string{}
+	proceed := true
+	if testCase.Elicitation != nil {
+		var elicited map[string]string
+		if result, elicited, proceed, err = s.runElicitation(rc, name, testCase); err != nil {
+			return ToolResult{}, err
+		}
+		for key, value := range elicited {
+			vars[key] = value
+		}
+	}
+	if proceed && testCase.Sampling != nil {
+		var sampled map[string]string
+		if result, sampled, err = s.runSampling(rc, name, testCase); err != nil {
+			return ToolResult{}, err
 		}
+		for key, value := range sampled {
+			vars[key] = value
+		}
+	}
+	if len(vars) > 0 {
+		result = interpolateResult(result, vars)
 	}
 
-	return testCase.Response
+	if err := sleepContext(rc.ctx, testCase.Delay); err != nil {
+		return ToolResult{}, err
+	}
+
+	return toolResultForSession(rc.session, tool, result), nil
+}
+
+// toolResultForSession prepares a test case's result for the session's protocol version
+// On 2025-06-18 and later a result that fails its tool's outputSchema is turned into an error
+// result; older sessions see neither outputSchema nor structuredContent, so it is not checked
+// for them. Content blocks the version does not know about are dropped, a text copy of
+// structuredContent is added for clients that only read content, and structuredContent is
+// dropped for sessions older than 2025-06-18
+func toolResultForSession(session *Session, tool Tool, result ToolResult) ToolResult {
+	structured := protocolAtLeast(session.ProtocolVersion(), ProtocolVersion20250618)
+	if schema, ok := tool.OutputSchema.(map[string]interface{}); ok && structured {
+		if err := checkStructuredContent(schema, result); err != nil {
+			log.Printf("Test case for tool %s is invalid: %v", tool.Name, err)
+			return errorResult(fmt.Sprintf("Invalid test case for tool %s: %v", tool.Name, err))
+		}
+	}
+
+	result.Content = contentForVersion(session, result.Content)
+	if result.StructuredContent == nil {
+		return result
+	}
+
+	if len(result.Content) == 0 {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			result.Content = []ContentBlock{{Type: "text", Text: string(data)}}
+		}
+	}
+	if !structured {
+		result.StructuredContent = nil
+	}
+	return result
 }
 
 // sendError sends an error response
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTestCaseNameCollisions(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "tools.yaml")
+	testcasesDir := filepath.Join(dir, "testcases")
+
+	config := `
+tools:
+  - name: lookup
+    description: Shares its name with a prompt
+  - name: search
+    description: Has its name to itself
+  - name: logs-service-completion
+    description: Shares its name with a completion
+prompts:
+  - name: lookup
+    description: Shares its name with a tool
+resourceTemplates:
+  - uriTemplate: "logs://{service}"
+    name: logs
+completions:
+  - resourceTemplate: "logs://{service}"
+    argument: service
+    mode: testcase
+`
+	files := map[string]string{
+		configPath: config,
+		filepath.Join(testcasesDir, "lookup-test-case-1.yaml"):                  "input: {}\n",
+		filepath.Join(testcasesDir, "search-test-case-1.yaml"):                  "input: {}\n",
+		filepath.Join(testcasesDir, "logs-service-completion-test-case-1.yaml"): "input: {}\n",
+	}
+	if err := os.Mkdir(testcasesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server, err := NewMockMCPServerWithTestcases(configPath, testcasesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	want := []string{
+		`test cases for "logs-service-completion" are shared by tool logs-service-completion, completion of logs://{service} argument service`,
+		`test cases for "lookup" are shared by tool lookup, prompt lookup`,
+	}
+	if got := server.testCaseNameCollisions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("testCaseNameCollisions() = %q, want %q", got, want)
+	}
+}
+
+func TestToolResultForSessionChecksStructuredContent(t *testing.T) {
+	tool := Tool{Name: "mock_weather", OutputSchema: parseSchema(t, `{type: object, required: [temperature]}`)}
+	invalid := ToolResult{Content: []ContentBlock{{Type: "text", Text: "21.5"}}}
+
+	tests := []struct {
+		version     string
+		wantIsError bool
+	}{
+		{ProtocolVersion20250618, true},
+		{ProtocolVersion20250326, false},
+		{ProtocolVersion20241105, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			session := newSession("stdio")
+			defer session.Close()
+			session.setProtocolVersion(tt.version)
+
+			if got := toolResultForSession(session, tool, invalid); got.IsError != tt.wantIsError {
+				t.Errorf("toolResultForSession() isError = %v, want %v", got.IsError, tt.wantIsError)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// sessionOutboundBuffer is the number of server-to-client messages queued per session
+const sessionOutboundBuffer = 100
+
+// Streamable HTTP sessions are not tied to a connection, so a client that goes away without
+// a DELETE would leave its session behind; sessions idle for sessionIdleTimeout are ended,
+// checked every sessionExpiryInterval
+const (
+	sessionIdleTimeout    = 30 * time.Minute
+	sessionExpiryInterval = time.Minute
+)
+
+// Session holds the state of a single client connection, whatever the transport
+type Session struct {
+	id        string
+	transport string
+
+	// ctx is cancelled when the session ends, aborting any requests still in flight
+	ctx      context.Context
+	cancel   context.CancelFunc
+	outbound chan interface{}
+
+	mutex              sync.RWMutex
+	lastActive         time.Time
+	protocolVersion    string
+	clientCapabilities map[string]interface{}
+	roots              []Root
+	logLevel           string
+	streamActive       bool
+	inFlight           map[string]context.CancelFunc
+
+	// Server-initiated requests awaiting the client's response, by request ID
+	// clientErr is set once the client can no longer answer them
+	pending       map[string]chan *MCPRequest
+	nextRequestID int
+	clientErr     error
+}
+
+// newSession creates a session that is not yet registered with a SessionManager
+func newSession(transport string) *Session {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Session{
+		id:         generateSessionID(),
+		transport:  transport,
+		lastActive: time.Now(),
+		ctx:        ctx,
+		cancel:     cancel,
+		outbound:   make(chan interface{}, sessionOutboundBuffer),
+		inFlight:   make(map[string]context.CancelFunc),
+		pending:    make(map[string]chan *MCPRequest),
+	}
+}
+
+// ID returns the session identifier
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Transport returns the name of the transport the session was created on
+func (s *Session) Transport() string {
+	return s.transport
+}
+
+// Send queues a message for delivery to the client on the session's stream
+// Returns false if the session has ended or its queue is full
+func (s *Session) Send(msg interface{}) bool {
+	select {
+	case <-s.ctx.Done():
+		return false
+	default:
+	}
+
+	select {
+	case s.outbound <- msg:
+		return true
+	default:
+		log.Printf("Outbound queue full for session %s, dropping message", s.id)
+		return false
+	}
+}
+
+// Close ends the session
+func (s *Session) Close() {
+	s.cancel()
+}
+
+// trackRequest registers an in-flight request so that it can be cancelled by ID
+// The returned function must be called once the request has finished
+func (s *Session) trackRequest(parent context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	key := requestIDKey(id)
+
+	s.mutex.Lock()
+	s.inFlight[key] = cancel
+	s.mutex.Unlock()
+
+	return ctx, func() {
+		s.mutex.Lock()
+		delete(s.inFlight, key)
+		s.lastActive = time.Now()
+		s.mutex.Unlock()
+		cancel()
+	}
+}
+
+// cancelRequest aborts an in-flight request, returning false if it is not in flight
+func (s *Session) cancelRequest(id interface{}) bool {
+	s.mutex.Lock()
+	cancel, exists := s.inFlight[requestIDKey(id)]
+	s.mutex.Unlock()
+
+	if exists {
+		cancel()
+	}
+	return exists
+}
+
+// awaitResponse allocates an ID for a server-initiated request and registers it so the
+// client's response can be delivered on the returned channel
+// The returned function must be called once the response has arrived or is no longer awaited
+// A nil response on the channel means the client can no longer answer (see clientError)
+func (s *Session) awaitResponse() (string, <-chan *MCPRequest, func(), error) {
+	s.mutex.Lock()
+	if s.clientErr != nil {
+		s.mutex.Unlock()
+		return "", nil, func() {}, s.clientErr
+	}
+	s.nextRequestID++
+	id := fmt.Sprintf("server-%d", s.nextRequestID)
+	key := requestIDKey(id)
+	ch := make(chan *MCPRequest, 1)
+	s.pending[key] = ch
+	s.mutex.Unlock()
+
+	return id, ch, func() {
+		s.mutex.Lock()
+		delete(s.pending, key)
+		s.mutex.Unlock()
+	}, nil
+}
+
+// deliverResponse hands a client's response to the server-initiated request awaiting it
+// Returns false if no request with that ID is pending
+func (s *Session) deliverResponse(resp *MCPRequest) bool {
+	key := requestIDKey(resp.ID)
+
+	s.mutex.Lock()
+	ch, exists := s.pending[key]
+	delete(s.pending, key)
+	s.mutex.Unlock()
+
+	if exists {
+		ch <- resp
+	}
+	return exists
+}
+
+// failClientRequests fails every server-initiated request awaiting the client's response,
+// and any made later, with err; used once nothing can carry the client's answer back
+func (s *Session) failClientRequests(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clientErr = err
+	for key, ch := range s.pending {
+		delete(s.pending, key)
+		ch <- nil
+	}
+}
+
+// clientError returns why the client can no longer answer server-initiated requests, or nil
+func (s *Session) clientError() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.clientErr
+}
+
+// requestIDKey normalises a JSON-RPC request ID (string or number) for use as a map key
+// The type is part of the key so that the number 1 and the string "1" stay distinct
+func requestIDKey(id interface{}) string {
+	return fmt.Sprintf("%T:%v", id, id)
+}
+
+// setProtocolVersion records the protocol version negotiated with the client
+func (s *Session) setProtocolVersion(version string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.protocolVersion = version
+}
+
+// setClientCapabilities records the capabilities the client declared in initialize
+func (s *Session) setClientCapabilities(capabilities map[string]interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clientCapabilities = capabilities
+}
+
+// hasClientCapability reports whether the client declared the named capability
+func (s *Session) hasClientCapability(name string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	_, exists := s.clientCapabilities[name]
+	return exists
+}
+
+// setRoots records the roots the client most recently returned for roots/list
+func (s *Session) setRoots(roots []Root) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.roots = roots
+}
+
+// Roots returns the client's roots as last reported, or nil if they are not known
+func (s *Session) Roots() []Root {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]Root(nil), s.roots...)
+}
+
+// setLogLevel records the minimum log level chosen by the client with logging/setLevel
+func (s *Session) setLogLevel(level string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.logLevel = level
+}
+
+// LogLevel returns the minimum log level chosen by the client, or "" if it has not chosen one
+func (s *Session) LogLevel() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.logLevel
+}
+
+// negotiatedProtocolVersion returns the negotiated protocol version, or "" if there is none
+func (s *Session) negotiatedProtocolVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.protocolVersion
+}
+
+// ProtocolVersion returns the protocol version governing the session's behaviour
+func (s *Session) ProtocolVersion() string {
+	if version := s.negotiatedProtocolVersion(); version != "" {
+		return version
+	}
+	return assumedProtocolVersion
+}
+
+// attachStream marks the session's standalone stream as open
+// Returns false if another stream is already attached
+func (s *Session) attachStream() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.streamActive {
+		return false
+	}
+	s.streamActive = true
+	return true
+}
+
+// detachStream marks the session's standalone stream as closed
+func (s *Session) detachStream() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.streamActive = false
+	s.lastActive = time.Now()
+}
+
+// touch records activity from the client
+func (s *Session) touch() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastActive = time.Now()
+}
+
+// idleExpired reports whether a Streamable HTTP session has seen no activity for timeout
+// A session with an open stream or requests in flight is never idle; sessions on other
+// transports end with their connection instead
+func (s *Session) idleExpired(timeout time.Duration) bool {
+	if s.transport != "streamable-http" {
+		return false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.streamActive || len(s.inFlight) > 0 || len(s.pending) > 0 {
+		return false
+	}
+	return time.Since(s.lastActive) > timeout
+}
+
+// generateSessionID returns a random, URL-safe session identifier
+func generateSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to a time-based ID if the system RNG is unavailable
+		return hex.EncodeToString([]byte(time.Now().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
+
+// SessionManager tracks active sessions across all transports
+// Idle Streamable HTTP sessions are ended in the background until CloseAll is called
+type SessionManager struct {
+	sessions map[string]*Session
+	mutex    sync.RWMutex
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSessionManager creates a new session manager
+func NewSessionManager() *SessionManager {
+	sm := &SessionManager{
+		sessions: make(map[string]*Session),
+		done:     make(chan struct{}),
+	}
+	go sm.expireIdleSessions(sessionIdleTimeout, sessionExpiryInterval)
+	return sm
+}
+
+// expireIdleSessions ends idle sessions every interval until the manager is closed
+func (sm *SessionManager) expireIdleSessions(timeout, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sm.done:
+			return
+		case <-ticker.C:
+			sm.mutex.RLock()
+			var expired []string
+			for id, session := range sm.sessions {
+				if session.idleExpired(timeout) {
+					expired = append(expired, id)
+				}
+			}
+			sm.mutex.RUnlock()
+
+			for _, id := range expired {
+				if sm.Remove(id) {
+					log.Printf("Session %s expired after %s without activity", id, timeout)
+				}
+			}
+		}
+	}
+}
+
+// Add registers a session
+func (sm *SessionManager) Add(session *Session) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.sessions[session.id] = session
+}
+
+// Get retrieves a session by ID (thread-safe)
+// Looking a session up counts as activity, as it is done for every client message
+func (sm *SessionManager) Get(id string) (*Session, bool) {
+	sm.mutex.RLock()
+	session, exists := sm.sessions[id]
+	sm.mutex.RUnlock()
+	if exists {
+		session.touch()
+	}
+	return session, exists
+}
+
+// Remove unregisters and closes a session, returning false if it did not exist
+func (sm *SessionManager) Remove(id string) bool {
+	sm.mutex.Lock()
+	session, exists := sm.sessions[id]
+	delete(sm.sessions, id)
+	sm.mutex.Unlock()
+
+	if exists {
+		session.Close()
+	}
+	return exists
+}
+
+// Broadcast queues a message on every active session
+func (sm *SessionManager) Broadcast(msg interface{}) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	for _, session := range sm.sessions {
+		session.Send(msg)
+	}
+}
+
+// CloseAll closes and unregisters every session and stops expiring idle sessions
+func (sm *SessionManager) CloseAll() {
+	sm.stopOnce.Do(func() { close(sm.done) })
+
+	sm.mutex.Lock()
+	sessions := sm.sessions
+	sm.sessions = make(map[string]*Session)
+	sm.mutex.Unlock()
+
+	for _, session := range sessions {
+		session.Close()
+	}
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestIDKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b interface{}
+		same bool
+	}{
+		{"same number", 1.0, 1.0, true},
+		{"same string", "1", "1", true},
+		{"number and string", 1.0, "1", false},
+		{"different numbers", 1.0, 2.0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestIDKey(tt.a) == requestIDKey(tt.b); got != tt.same {
+				t.Errorf("requestIDKey(%#v) == requestIDKey(%#v) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestCancelRequestMatchesIDType(t *testing.T) {
+	session := newSession("stdio")
+	defer session.Close()
+
+	ctx, done := session.trackRequest(context.Background(), 1.0)
+	defer done()
+
+	if session.cancelRequest("1") {
+		t.Fatal(`cancelRequest("1") cancelled the request with numeric ID 1`)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("request was cancelled by a string ID")
+	}
+	if !session.cancelRequest(1.0) {
+		t.Fatal("cancelRequest(1) did not find the request with numeric ID 1")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("request was not cancelled")
+	}
+}
+
+func TestDeliverResponse(t *testing.T) {
+	session := newSession("stdio")
+	defer session.Close()
+
+	id, ch, done, err := session.awaitResponse()
+	defer done()
+	if err != nil {
+		t.Fatalf("awaitResponse() error = %v", err)
+	}
+
+	if !session.deliverResponse(&MCPRequest{ID: id}) {
+		t.Fatalf("deliverResponse(%q) found no pending request", id)
+	}
+	if resp := <-ch; resp.ID != id {
+		t.Errorf("delivered response ID = %v, want %q", resp.ID, id)
+	}
+}
+
+func TestFailClientRequests(t *testing.T) {
+	session := newSession("stdio")
+	defer session.Close()
+
+	_, ch, done, err := session.awaitResponse()
+	defer done()
+	if err != nil {
+		t.Fatalf("awaitResponse() error = %v", err)
+	}
+
+	session.failClientRequests(errClientDisconnected)
+	if resp := <-ch; resp != nil {
+		t.Errorf("pending request got %v, want nil", resp)
+	}
+	if _, _, _, err := session.awaitResponse(); err != errClientDisconnected {
+		t.Errorf("awaitResponse() after failClientRequests error = %v, want %v", err, errClientDisconnected)
+	}
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// legacyMessagesPath is the endpoint advertised to HTTP+SSE clients for posting messages
+const legacyMessagesPath = "/messages"
+
+// HandleSSE opens a session on the legacy (2024-11-05) HTTP+SSE transport
+// The client receives an endpoint event naming the URL to POST messages to, and every
+// response is then delivered over this stream
+func (s *MockMCPServer) HandleSSE(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	session := newSession("sse")
+	session.attachStream()
+	s.sessions.Add(session)
+	defer s.sessions.Remove(session.ID())
+
+	setSSEHeaders(w)
+	w.WriteHeader(http.StatusOK)
+
+	endpoint := fmt.Sprintf("%s?sessionId=%s", legacyMessagesPath, session.ID())
+	if _, err := fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", endpoint); err != nil {
+		log.Printf("SSE write error for session %s: %v", session.ID(), err)
+		return
+	}
+	flusher.Flush()
+
+	log.Printf("Started %s session %s", session.Transport(), session.ID())
+	s.pumpSSE(w, flusher, r, session)
+	log.Printf("Ended %s session %s", session.Transport(), session.ID())
+}
+
+// HandleMessages accepts a JSON-RPC message for a legacy HTTP+SSE session
+// The POST is acknowledged immediately and the response is sent on the session's SSE stream
+func (s *MockMCPServer) HandleMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := r.URL.Query().Get("sessionId")
+	if sessionID == "" {
+		http.Error(w, "Missing sessionId query parameter", http.StatusBadRequest)
+		return
+	}
+
+	// Sessions of other transports share the session manager but are not reachable from here
+	session, exists := s.sessions.Get(sessionID)
+	if !exists || session.Transport() != "sse" {
+		http.Error(w, fmt.Sprintf("session not found: %s", sessionID), http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+
+	go func() {
+		rc := newRequestContext(session.ctx, session)
+		reply := s.processMessage(rc, body)
+		if reply == nil {
+			return
+		}
+		if !session.Send(reply) {
+			log.Printf("Dropped reply for session %s", session.ID())
+		}
+	}()
+}
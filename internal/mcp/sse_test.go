@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleMessagesOnlyAcceptsSSESessions(t *testing.T) {
+	server := &MockMCPServer{sessions: NewSessionManager(), journal: NewRequestJournal()}
+	defer server.sessions.CloseAll()
+
+	tests := []struct {
+		transport  string
+		wantStatus int
+	}{
+		{"sse", http.StatusAccepted},
+		{"streamable-http", http.StatusNotFound},
+		{"websocket", http.StatusNotFound},
+		{"stdio", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transport, func(t *testing.T) {
+			session := newSession(tt.transport)
+			server.sessions.Add(session)
+
+			// A notification needs no reply, so nothing is queued on the session
+			body := strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+			r := httptest.NewRequest(http.MethodPost, "/messages?sessionId="+session.ID(), body)
+			w := httptest.NewRecorder()
+			server.HandleMessages(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("POST /messages for a %s session = %d, want %d", tt.transport, w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// maxStdioMessageSize is the largest newline-delimited message accepted on stdin
+const maxStdioMessageSize = 10 * 1024 * 1024
+
+// errClientDisconnected fails requests to the client once stdin has closed
+var errClientDisconnected = errors.New("client disconnected")
+
+// stdioWriter serialises newline-delimited JSON-RPC messages onto stdout
+type stdioWriter struct {
+	encoder *json.Encoder
+	mutex   sync.Mutex
+}
+
+// write encodes a message as a single line (thread-safe)
+func (sw *stdioWriter) write(msg interface{}) error {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	return sw.encoder.Encode(msg)
+}
+
+// ServeStdio runs the MCP protocol over newline-delimited JSON-RPC on in/out
+// It returns when in reaches EOF. Logs must be sent elsewhere (e.g. stderr) so they
+// don't corrupt the protocol stream
+func (s *MockMCPServer) ServeStdio(in io.Reader, out io.Writer) error {
+	writer := &stdioWriter{encoder: json.NewEncoder(out)}
+
+	session := newSession("stdio")
+	s.sessions.Add(session)
+	defer s.sessions.Remove(session.ID())
+
+	// Deliver server-initiated messages queued on the session
+	go func() {
+		for {
+			select {
+			case msg := <-session.outbound:
+				if err := writer.write(msg); err != nil {
+					log.Printf("stdio write error: %v", err)
+				}
+			case <-session.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("Started %s session %s", session.Transport(), session.ID())
+
+	var inFlight sync.WaitGroup
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessageSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		// Requests run concurrently so that slow calls can be cancelled
+		inFlight.Add(1)
+		go func(data []byte) {
+			defer inFlight.Done()
+			rc := &requestContext{ctx: session.ctx, session: session, send: writer.write}
+			reply := s.processMessage(rc, data)
+			if reply == nil {
+				return
+			}
+			if err := writer.write(reply); err != nil {
+				log.Printf("stdio write error: %v", err)
+			}
+		}(append([]byte(nil), line...))
+	}
+
+	// No client can answer any more, so requests waiting on one (elicitation, sampling, roots)
+	// fail; everything else still finishes and is answered before the session ends
+	log.Printf("stdin closed, ending %s session %s", session.Transport(), session.ID())
+	session.failClientRequests(errClientDisconnected)
+	inFlight.Wait()
+	session.Close()
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read from stdin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeStdioAnswersRequestsBeforeEOF(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(configPath, []byte("tools:\n  - name: mock_echo\n    description: Echoes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewMockMCPServer(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := server.ServeStdio(strings.NewReader(in), &out); err != nil {
+		t.Fatalf("ServeStdio() error = %v", err)
+	}
+
+	answered := make(map[float64]bool)
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var resp MCPResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid output line %q: %v", scanner.Text(), err)
+		}
+		if resp.Error != nil {
+			t.Errorf("request %v failed: %s", resp.ID, resp.Error.Message)
+		}
+		if id, ok := resp.ID.(float64); ok {
+			answered[id] = true
+		}
+	}
+	for _, id := range []float64{1, 2, 3} {
+		if !answered[id] {
+			t.Errorf("request %v was not answered; output:\n%s", id, out.String())
+		}
+	}
+}
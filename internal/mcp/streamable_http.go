@@ -0,0 +1,327 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionIDHeader is the header used by the Streamable HTTP transport to carry the session ID
+const sessionIDHeader = "Mcp-Session-Id"
+
+// streamKeepAliveInterval is how often an idle SSE stream receives a keep-alive comment
+const streamKeepAliveInterval = 30 * time.Second
+
+// errStatelessRequest fails requests to the client on a request without a session: the
+// client's response would arrive without a session ID and could not be matched to it
+var errStatelessRequest = errors.New("the request has no session to carry the client's response")
+
+// handleHTTPRequest handles a JSON-RPC message or batch POSTed to the Streamable HTTP endpoint
+func (s *MockMCPServer) handleHTTPRequest(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if isBatch(body) {
+		session, status, err := s.resolveHTTPSession(r, "")
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		if session.Transport() == "http" {
+			defer session.Close()
+		}
+		if err := s.checkProtocolVersionHeader(r, session); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stream := newPostStream(w, r, session)
+		rc := &requestContext{ctx: r.Context(), session: session, send: stream.send}
+		stream.respond(s.processBatch(rc, body))
+		return
+	}
+
+	var req MCPRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		s.sendError(w, nil, -32700, "Parse error", err.Error())
+		return
+	}
+
+	session, status, err := s.resolveHTTPSession(r, req.Method)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if session.Transport() == "http" {
+		defer session.Close()
+	}
+	if err := s.checkProtocolVersionHeader(r, session); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream := newPostStream(w, r, session)
+	rc := &requestContext{ctx: r.Context(), session: session, send: stream.send}
+	response := s.processRequest(rc, &req)
+
+	// A successful initialize starts a new session; the client echoes its ID on every later request
+	if req.Method == "initialize" && response != nil && response.Error == nil {
+		s.sessions.Add(session)
+		w.Header().Set(sessionIDHeader, session.ID())
+		w.Header().Set("Access-Control-Expose-Headers", sessionIDHeader)
+		log.Printf("Started %s session %s", session.Transport(), session.ID())
+	} else if req.Method == "initialize" {
+		session.Close()
+	}
+
+	// respond must see an untyped nil to know there is nothing to send
+	if response == nil {
+		stream.respond(nil)
+		return
+	}
+	stream.respond(response)
+}
+
+// resolveHTTPSession returns the session a POSTed message belongs to
+// initialize always gets a fresh session; requests without a session header are served
+// statelessly so that plain JSON-RPC clients keep working
+// A stateless session lasts for a single request and must be closed once it has been answered
+func (s *MockMCPServer) resolveHTTPSession(r *http.Request, method string) (*Session, int, error) {
+	if method == "initialize" {
+		return newSession("streamable-http"), http.StatusOK, nil
+	}
+
+	sessionID := r.Header.Get(sessionIDHeader)
+	if sessionID == "" {
+		session := newSession("http")
+		session.failClientRequests(errStatelessRequest)
+		return session, http.StatusOK, nil
+	}
+
+	session, exists := s.streamableSession(sessionID)
+	if !exists {
+		return nil, http.StatusNotFound, fmt.Errorf("session not found: %s", sessionID)
+	}
+	return session, http.StatusOK, nil
+}
+
+// streamableSession looks up a Streamable HTTP session by ID
+// Sessions of other transports share the session manager but are not reachable from this endpoint
+func (s *MockMCPServer) streamableSession(sessionID string) (*Session, bool) {
+	session, exists := s.sessions.Get(sessionID)
+	if !exists || session.Transport() != "streamable-http" {
+		return nil, false
+	}
+	return session, true
+}
+
+// postStream answers a single POST on the Streamable HTTP transport
+// The answer is plain JSON unless the client only accepts SSE, or the handler sends messages
+// before its response and the client accepts SSE, in which case it switches to an SSE stream
+type postStream struct {
+	w           http.ResponseWriter
+	flusher     http.Flusher
+	session     *Session
+	canStream   bool
+	forceStream bool
+	streaming   bool
+	mutex       sync.Mutex
+}
+
+// newPostStream creates the responder for a POST
+func newPostStream(w http.ResponseWriter, r *http.Request, session *Session) *postStream {
+	flusher, ok := w.(http.Flusher)
+	canStream := ok && (acceptsMediaType(r, "text/event-stream") || r.URL.Query().Get("stream") == "true")
+	return &postStream{
+		w:           w,
+		flusher:     flusher,
+		session:     session,
+		canStream:   canStream,
+		forceStream: canStream && wantsEventStream(r),
+	}
+}
+
+// startStream commits the response to an SSE stream
+func (ps *postStream) startStream() {
+	setSSEHeaders(ps.w)
+	ps.w.WriteHeader(http.StatusOK)
+	ps.flusher.Flush()
+	ps.streaming = true
+}
+
+// send delivers a message tied to the request before its response
+// Clients that only accept JSON get it on the session's GET stream instead, which a
+// stateless request does not have, so the message is dropped
+func (ps *postStream) send(msg interface{}) error {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	if !ps.canStream {
+		if ps.session.Transport() != "http" {
+			ps.session.Send(msg)
+		}
+		return nil
+	}
+	if !ps.streaming {
+		ps.startStream()
+	}
+	return writeSSEEvent(ps.w, ps.flusher, "message", msg)
+}
+
+// respond writes the final response (or batch of responses), closing the stream if one was opened
+// A nil response (e.g. a cancelled request) closes the stream or answers 202 Accepted
+func (ps *postStream) respond(response interface{}) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	if response == nil {
+		if !ps.streaming {
+			ps.w.WriteHeader(http.StatusAccepted)
+		}
+		return
+	}
+
+	if ps.forceStream && !ps.streaming {
+		ps.startStream()
+	}
+	if ps.streaming {
+		if err := writeSSEEvent(ps.w, ps.flusher, "message", response); err != nil {
+			log.Printf("SSE write error: %v", err)
+		}
+		return
+	}
+
+	ps.w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(ps.w).Encode(response)
+}
+
+// handleSessionStream handles GET requests that open the server-to-client SSE stream of a session
+func (s *MockMCPServer) handleSessionStream(w http.ResponseWriter, r *http.Request) {
+	if !acceptsMediaType(r, "text/event-stream") {
+		http.Error(w, "Accept header must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+
+	sessionID := r.Header.Get(sessionIDHeader)
+	if sessionID == "" {
+		http.Error(w, fmt.Sprintf("Missing %s header", sessionIDHeader), http.StatusBadRequest)
+		return
+	}
+
+	session, exists := s.streamableSession(sessionID)
+	if !exists {
+		http.Error(w, fmt.Sprintf("session not found: %s", sessionID), http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if !session.attachStream() {
+		http.Error(w, "A stream is already open for this session", http.StatusConflict)
+		return
+	}
+	defer session.detachStream()
+
+	setSSEHeaders(w)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	log.Printf("Opened SSE stream for session %s", session.ID())
+	s.pumpSSE(w, flusher, r, session)
+	log.Printf("Closed SSE stream for session %s", session.ID())
+}
+
+// pumpSSE delivers a session's outbound messages as SSE events until the client or session goes away
+func (s *MockMCPServer) pumpSSE(w http.ResponseWriter, flusher http.Flusher, r *http.Request, session *Session) {
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case msg := <-session.outbound:
+			if err := writeSSEEvent(w, flusher, "message", msg); err != nil {
+				log.Printf("SSE write error for session %s: %v", session.ID(), err)
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-session.ctx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleSessionDelete handles DELETE requests that explicitly end a session
+func (s *MockMCPServer) handleSessionDelete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(sessionIDHeader)
+	if sessionID == "" {
+		http.Error(w, fmt.Sprintf("Missing %s header", sessionIDHeader), http.StatusBadRequest)
+		return
+	}
+
+	if _, exists := s.streamableSession(sessionID); !exists || !s.sessions.Remove(sessionID) {
+		http.Error(w, fmt.Sprintf("session not found: %s", sessionID), http.StatusNotFound)
+		return
+	}
+
+	log.Printf("Ended session %s", sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setSSEHeaders sets the response headers for a Server-Sent Events stream
+func setSSEHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+}
+
+// writeSSEEvent writes a single SSE event with a JSON-encoded payload
+func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SSE payload: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// wantsEventStream reports whether a POST should be answered with an SSE stream
+// JSON is preferred whenever the client accepts it; stream=true is kept for older clients
+func wantsEventStream(r *http.Request) bool {
+	if r.URL.Query().Get("stream") == "true" {
+		return true
+	}
+	return acceptsMediaType(r, "text/event-stream") && !acceptsMediaType(r, "application/json")
+}
+
+// acceptsMediaType reports whether the request's Accept header lists the given media type
+func acceptsMediaType(r *http.Request, mediaType string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if strings.TrimSpace(strings.SplitN(part, ";", 2)[0]) == mediaType {
+			return true
+		}
+	}
+	return false
+}
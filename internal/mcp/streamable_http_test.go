@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamableHTTPRejectsOtherTransports(t *testing.T) {
+	server := &MockMCPServer{sessions: NewSessionManager()}
+	defer server.sessions.CloseAll()
+
+	for _, transport := range []string{"sse", "websocket", "stdio"} {
+		t.Run(transport, func(t *testing.T) {
+			session := newSession(transport)
+			server.sessions.Add(session)
+
+			get := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+			get.Header.Set("Accept", "text/event-stream")
+			get.Header.Set(sessionIDHeader, session.ID())
+			w := httptest.NewRecorder()
+			server.handleSessionStream(w, get)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("GET /mcp = %d, want %d", w.Code, http.StatusNotFound)
+			}
+
+			del := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+			del.Header.Set(sessionIDHeader, session.ID())
+			w = httptest.NewRecorder()
+			server.handleSessionDelete(w, del)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("DELETE /mcp = %d, want %d", w.Code, http.StatusNotFound)
+			}
+			if _, exists := server.sessions.Get(session.ID()); !exists {
+				t.Error("DELETE /mcp ended the session")
+			}
+			if session.ctx.Err() != nil {
+				t.Error("DELETE /mcp closed the session")
+			}
+		})
+	}
+}
+
+func TestStreamableHTTPDeletesOwnSessions(t *testing.T) {
+	server := &MockMCPServer{sessions: NewSessionManager()}
+	defer server.sessions.CloseAll()
+
+	session := newSession("streamable-http")
+	server.sessions.Add(session)
+
+	r := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+	r.Header.Set(sessionIDHeader, session.ID())
+	w := httptest.NewRecorder()
+	server.handleSessionDelete(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("DELETE /mcp = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if _, exists := server.sessions.Get(session.ID()); exists {
+		t.Error("DELETE /mcp left the session registered")
+	}
+}
+
+func TestStatelessSessionFailsClientRequests(t *testing.T) {
+	server := &MockMCPServer{sessions: NewSessionManager()}
+	defer server.sessions.CloseAll()
+
+	session, _, err := server.resolveHTTPSession(httptest.NewRequest(http.MethodPost, "/mcp", nil), "tools/call")
+	if err != nil {
+		t.Fatalf("resolveHTTPSession() error = %v", err)
+	}
+	defer session.Close()
+
+	rc := &requestContext{ctx: session.ctx, session: session, send: func(interface{}) error { return nil }}
+	done := make(chan error, 1)
+	go func() {
+		_, err := rc.request("sampling/createMessage", nil, time.Minute)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errStatelessRequest) {
+			t.Errorf("request() error = %v, want %v", err, errStatelessRequest)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request() on a stateless session waited for a response")
+	}
+}
+
+func TestProtocolVersionHeaderMustMatchSession(t *testing.T) {
+	server := &MockMCPServer{sessions: NewSessionManager(), toolManager: &ToolManager{}}
+	defer server.sessions.CloseAll()
+
+	session := newSession("streamable-http")
+	session.setProtocolVersion(ProtocolVersion20250326)
+	server.sessions.Add(session)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"request", `{"jsonrpc":"2.0","id":1,"method":"ping"}`},
+		{"batch", `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(tt.body))
+			r.Header.Set(sessionIDHeader, session.ID())
+			r.Header.Set(protocolVersionHeader, ProtocolVersion20241105)
+			w := httptest.NewRecorder()
+			server.handleHTTPRequest(w, r)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("POST with a mismatched %s = %d, want %d", protocolVersionHeader, w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.Header.Set(protocolVersionHeader, ProtocolVersion20250326)
+	if err := server.checkProtocolVersionHeader(r, session); err != nil {
+		t.Errorf("checkProtocolVersionHeader() with the negotiated version: %v", err)
+	}
+	if version := session.ProtocolVersion(); version != ProtocolVersion20250326 {
+		t.Errorf("session protocol version = %s, want %s", version, ProtocolVersion20250326)
+	}
+}
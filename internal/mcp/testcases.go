@@ -5,13 +5,70 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 	"gopkg.in/yaml.v3"
 )
 
+// testCaseReloadDelay is how long the test cases must stay unchanged before they are reloaded
+const testCaseReloadDelay = 100 * time.Millisecond
+
+// Test case file names in the testcases directory
+var (
+	// <tool>-test-case-<N>.yaml, capturing the tool name and the test case number
+	testCaseFilePattern = regexp.MustCompile(`^(.+)-test-case-(\d+)\.yaml$`)
+	// <tool>-test-cases.yaml, capturing the tool name
+	testCaseListFilePattern = regexp.MustCompile(`^(.+)-test-cases\.yaml$`)
+	// test-case-<N>.yaml inside a <tool>/ subdirectory, capturing the test case number
+	toolDirTestCasePattern = regexp.MustCompile(`^test-case-(\d+)\.yaml$`)
+)
+
 // TestCaseManager handles loading and matching test cases
+// All test cases are loaded into memory at startup and reloaded whenever the testcases directory changes
 type TestCaseManager struct {
 	testCasesDir string
+	index        map[string][]indexedTestCase // Test cases by tool name, in test case number order
+	patterns     map[string]*regexp.Regexp    // Compiled $regex patterns of the test cases; nil if invalid
+	indexMutex   sync.RWMutex
+	watcher      *fsnotify.Watcher
+	reloadTimer  *time.Timer
+	closed       bool
+	onReload     func()
+}
+
+// indexedTestCase is a loaded test case and where it came from
+// The test case is shared by every call that matches it and must not be modified
+type indexedTestCase struct {
+	number   int    // Test case number for numbered files, 0 for other files
+	file     string // Path relative to the testcases directory
+	position int    // Position in the file's testCases list, 0 for a single test case
+	list     bool   // Whether the file holds a testCases list
+	testCase *TestCaseConfig
+}
+
+// label describes a test case for logs: its name, or else its file and list position
+func (e indexedTestCase) label() string {
+	location := e.file
+	if e.list {
+		location = fmt.Sprintf("%s[%d]", e.file, e.position)
+	}
+	if e.testCase.Name != "" {
+		return fmt.Sprintf("%q (%s)", e.testCase.Name, location)
+	}
+	return location
+}
+
+// testCaseFile is the contents of a test case file: a single test case, or a list of them
+type testCaseFile struct {
+	TestCaseConfig `yaml:",inline"`
+	TestCases      []TestCaseConfig `yaml:"testCases,omitempty"`
 }
 
 // NewTestCaseManager creates a new test case manager
@@ -21,98 +78,438 @@ func NewTestCaseManager(configPath string) *TestCaseManager {
 
 // NewTestCaseManagerWithDir creates a new test case manager with optional testcases directory
 func NewTestCaseManagerWithDir(configPath, testcasesDir string) *TestCaseManager {
-	// If testcasesDir is provided, use it directly
-	if testcasesDir != "" {
-		return &TestCaseManager{
-			testCasesDir: testcasesDir,
+	tcm := &TestCaseManager{
+		testCasesDir: testcasesDir,
+		index:        make(map[string][]indexedTestCase),
+		patterns:     make(map[string]*regexp.Regexp),
+	}
+
+	// If testcasesDir is not provided, determine it based on config path location
+	if testcasesDir == "" {
+		configDir := filepath.Dir(configPath)
+		if configDir == "" || configDir == "." {
+			configDir, _ = os.Getwd()
+		}
+
+		// Use testcases/ directory at the same level as config directory
+		// e.g., if config is at /app/config/tools.yaml, testcases should be at /app/testcases
+		// If config is at ./config/tools.yaml, testcases should be at ./testcases
+		parentDir := filepath.Dir(configDir)
+		tcm.testCasesDir = filepath.Join(parentDir, "testcases")
+
+		// Fallback: if parent/testcases doesn't exist, try config/testcases (for local dev)
+		if _, err := os.Stat(tcm.testCasesDir); os.IsNotExist(err) {
+			fallbackDir := filepath.Join(configDir, "testcases")
+			if _, err := os.Stat(fallbackDir); err == nil {
+				tcm.testCasesDir = fallbackDir
+			}
 		}
 	}
 
-	// Determine test cases directory based on config path location
-	configDir := filepath.Dir(configPath)
-	if configDir == "" || configDir == "." {
-		configDir, _ = os.Getwd()
+	tcm.reload()
+
+	// Start watching the testcases directory for changes
+	if err := tcm.startFileWatcher(); err != nil {
+		log.Printf("Warning: Failed to watch test cases directory %s: %v", tcm.testCasesDir, err)
 	}
 
-	// Use testcases/ directory at the same level as config directory
-	// e.g., if config is at /app/config/tools.yaml, testcases should be at /app/testcases
-	// If config is at ./config/tools.yaml, testcases should be at ./testcases
-	parentDir := filepath.Dir(configDir)
-	testCasesDir := filepath.Join(parentDir, "testcases")
+	return tcm
+}
+
+// SetReloadCallback registers a function called after every reload of the test cases
+func (tcm *TestCaseManager) SetReloadCallback(fn func()) {
+	tcm.indexMutex.Lock()
+	defer tcm.indexMutex.Unlock()
+	tcm.onReload = fn
+}
 
-	// Fallback: if parent/testcases doesn't exist, try config/testcases (for local dev)
-	if _, err := os.Stat(testCasesDir); os.IsNotExist(err) {
-		fallbackDir := filepath.Join(configDir, "testcases")
-		if _, err := os.Stat(fallbackDir); err == nil {
-			testCasesDir = fallbackDir
+// FindMatchingTestCase finds a test case that matches the given tool name, arguments and client roots
+// defaultTestCase: 0 = no default, 1+ = use test-case-N as default if no match found
+// matchMode: the match mode of test cases that set none ("" = subset)
+func (tcm *TestCaseManager) FindMatchingTestCase(toolName string, args map[string]interface{}, roots []Root, defaultTestCase int, matchMode string) (*TestCaseConfig, error) {
+	log.Printf("Finding test case for: %s with args: %v and roots: %v (searching in: %s, defaultTestCase: %d, match: %s)", toolName, args, roots, tcm.testCasesDir, defaultTestCase, matchMode)
+
+	testCases := tcm.testCasesFor(toolName)
+
+	// Try test cases in order: highest priority first, then by number, then by file
+	for _, entry := range testCases {
+		testCase := entry.testCase
+
+		// Check if input arguments and roots match
+		exact := tcm.exactMatch(testCase, matchMode)
+		if extra := extraArguments(testCase.Input, args); exact && len(extra) > 0 {
+			log.Printf("Test case %s did not match. Unexpected arguments in exact match mode: %v", entry.label(), extra)
+		} else if !tcm.matchArguments(testCase.Input, args) {
+			log.Printf("Test case %s did not match. Expected: %v, Got: %v", entry.label(), testCase.Input, args)
+		} else if !tcm.matchRoots(testCase.Roots, roots) {
+			log.Printf("Test case %s did not match. Expected roots: %v, Got: %v", entry.label(), testCase.Roots, roots)
+		} else {
+			log.Printf("Matched test case: %s", entry.label())
+			return testCase, nil
 		}
 	}
 
-	return &TestCaseManager{
-		testCasesDir: testCasesDir,
+	// If no match found and defaultTestCase is configured, use the specified default
+	if defaultTestCase > 0 {
+		for _, entry := range testCases {
+			if entry.number == defaultTestCase {
+				log.Printf("Using configured default test case (%d): %s", defaultTestCase, entry.label())
+				return entry.testCase, nil
+			}
+		}
+		log.Printf("Configured default test case %d not found for %s", defaultTestCase, toolName)
 	}
+
+	return nil, fmt.Errorf("no matching test case found")
 }
 
-// FindMatchingTestCase finds a test case that matches the given tool name and arguments
-// defaultTestCase: 0 = no default, 1+ = use test-case-N as default if no match found
-func (tcm *TestCaseManager) FindMatchingTestCase(toolName string, args map[string]interface{}, defaultTestCase int) (*TestCaseConfig, error) {
-	log.Printf("Finding test case for tool: %s with args: %v (searching in: %s, defaultTestCase: %d)", toolName, args, tcm.testCasesDir, defaultTestCase)
+// testCasesFor returns the loaded test cases of a tool, in matching order (thread-safe)
+func (tcm *TestCaseManager) testCasesFor(toolName string) []indexedTestCase {
+	tcm.indexMutex.RLock()
+	defer tcm.indexMutex.RUnlock()
+	return tcm.index[toolName]
+}
 
-	// Try test cases in order (1, 2, 3, ...) up to a reasonable limit
-	for i := 1; i <= 100; i++ {
-		testCaseFile := filepath.Join(tcm.testCasesDir, fmt.Sprintf("%s-test-case-%d.yaml", toolName, i))
+// reload loads every test case in the testcases directory and replaces the index in one step,
+// so lookups see either the old or the new test cases, never a mix
+// Test cases come from <tool>-test-case-<N>.yaml and <tool>-test-cases.yaml files, and from
+// every YAML file in a <tool>/ subdirectory; files that fail to load are logged and left out
+func (tcm *TestCaseManager) reload() {
+	entries, err := os.ReadDir(tcm.testCasesDir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Error reading test cases directory %s: %v", tcm.testCasesDir, err)
+		return
+	}
 
-		// Check if file exists
-		if _, err := os.Stat(testCaseFile); os.IsNotExist(err) {
-			continue
+	index := make(map[string][]indexedTestCase)
+	add := func(toolName, file string, number int) {
+		loaded, list, err := tcm.loadTestCases(filepath.Join(tcm.testCasesDir, file))
+		if err != nil {
+			log.Printf("Error loading test case %s: %v", file, err)
+			return
 		}
+		for i, testCase := range loaded {
+			index[toolName] = append(index[toolName], indexedTestCase{number: number, file: file, position: i, list: list, testCase: testCase})
+		}
+	}
 
-		// Load test case
-		testCase, err := tcm.loadTestCase(testCaseFile)
-		if err != nil {
-			log.Printf("Error loading test case %s: %v", testCaseFile, err)
-			continue
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			if !isToolDir(name) {
+				continue
+			}
+			// A per-tool subdirectory; its YAML files may have any name
+			files, err := os.ReadDir(filepath.Join(tcm.testCasesDir, name))
+			if err != nil {
+				log.Printf("Error reading test cases directory %s: %v", name, err)
+				continue
+			}
+			for _, file := range files {
+				if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+					continue
+				}
+				number := 0
+				if match := toolDirTestCasePattern.FindStringSubmatch(file.Name()); match != nil {
+					number, _ = strconv.Atoi(match[1])
+				}
+				add(name, filepath.Join(name, file.Name()), number)
+			}
+		} else if match := testCaseFilePattern.FindStringSubmatch(name); match != nil {
+			number, err := strconv.Atoi(match[2])
+			if err != nil {
+				continue
+			}
+			add(match[1], name, number)
+		} else if match := testCaseListFilePattern.FindStringSubmatch(name); match != nil {
+			add(match[1], name, 0)
 		}
+	}
 
-		// Check if input arguments match
-		if tcm.matchArguments(testCase.Input, args) {
-			log.Printf("Matched test case: %s", testCaseFile)
-			return testCase, nil
-		} else {
-			log.Printf("Test case %s did not match. Expected: %v, Got: %v", testCaseFile, testCase.Input, args)
+	count := 0
+	patterns := make(map[string]*regexp.Regexp)
+	for toolName, testCases := range index {
+		sortTestCases(testCases)
+		count += len(testCases)
+
+		for _, entry := range testCases {
+			compilePatterns(entry.testCase.Input, patterns, entry.label())
+		}
+
+		names := make(map[string]string)
+		for _, entry := range testCases {
+			if name := entry.testCase.Name; name != "" {
+				if previous, exists := names[name]; exists {
+					log.Printf("Warning: test cases %s and %s of %s have the same name", previous, entry.label(), toolName)
+				}
+				names[name] = entry.label()
+			}
 		}
 	}
 
-	// If no match found and defaultTestCase is configured, use the specified default
-	if defaultTestCase > 0 {
-		defaultFile := filepath.Join(tcm.testCasesDir, fmt.Sprintf("%s-test-case-%d.yaml", toolName, defaultTestCase))
-		if _, err := os.Stat(defaultFile); err == nil {
-			testCase, err := tcm.loadTestCase(defaultFile)
-			if err == nil {
-				log.Printf("Using configured default test case (%d): %s", defaultTestCase, defaultFile)
-				return testCase, nil
+	tcm.indexMutex.Lock()
+	tcm.index = index
+	tcm.patterns = patterns
+	onReload := tcm.onReload
+	tcm.indexMutex.Unlock()
+
+	log.Printf("Loaded %d test case(s) for %d name(s) from %s", count, len(index), tcm.testCasesDir)
+	if onReload != nil {
+		onReload()
+	}
+}
+
+// fixturesDir is the testcases subdirectory holding files that test cases load content from
+const fixturesDir = "fixtures"
+
+// isToolDir reports whether a testcases subdirectory holds a tool's test cases
+// The fixtures directory and hidden directories such as .git do not
+func isToolDir(name string) bool {
+	return name != fixturesDir && !strings.HasPrefix(name, ".")
+}
+
+// sortTestCases puts a tool's test cases in matching order: highest priority first; at equal
+// priority numbered test cases by number, then the others by file and position in the file
+func sortTestCases(testCases []indexedTestCase) {
+	sort.SliceStable(testCases, func(i, j int) bool {
+		a, b := testCases[i], testCases[j]
+		if a.testCase.Priority != b.testCase.Priority {
+			return a.testCase.Priority > b.testCase.Priority
+		}
+		if (a.number > 0) != (b.number > 0) {
+			return a.number > 0
+		}
+		if a.number != b.number {
+			return a.number < b.number
+		}
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		return a.position < b.position
+	})
+}
+
+// startFileWatcher starts watching the testcases directory, and the directories inside it
+// such as fixtures, for changes
+// The parent directory is watched as well, so that the testcases directory is picked up again
+// when it is created or replaced, as a GitHub sync does
+func (tcm *TestCaseManager) startFileWatcher() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(filepath.Dir(tcm.testCasesDir)); err != nil {
+		watcher.Close()
+		return err
+	}
+	tcm.watcher = watcher
+	if err := tcm.watchTree(tcm.testCasesDir); err != nil && !os.IsNotExist(err) {
+		watcher.Close()
+		return err
+	}
+
+	go tcm.watchFileChanges()
+
+	return nil
+}
+
+// watchTree adds a directory and every directory inside it to the watcher
+func (tcm *TestCaseManager) watchTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		return tcm.watcher.Add(path)
+	})
+}
+
+// inTestCasesDir reports whether a path is the testcases directory or lies inside it
+func (tcm *TestCaseManager) inTestCasesDir(path string) bool {
+	rel, err := filepath.Rel(tcm.testCasesDir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// watchFileChanges reloads the test cases when a file in the testcases directory changes
+// A burst of changes, such as a git pull, results in a single reload
+func (tcm *TestCaseManager) watchFileChanges() {
+	for {
+		select {
+		case event, ok := <-tcm.watcher.Events:
+			if !ok {
+				return
 			}
-		} else {
-			log.Printf("Configured default test case %d not found: %s", defaultTestCase, defaultFile)
+			if event.Op == fsnotify.Chmod || !tcm.inTestCasesDir(event.Name) {
+				continue
+			}
+
+			// New directories are watched too
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := tcm.watchTree(event.Name); err != nil {
+						log.Printf("Failed to watch %s: %v", event.Name, err)
+					}
+				}
+			}
+
+			tcm.indexMutex.Lock()
+			if tcm.closed {
+				tcm.indexMutex.Unlock()
+				return
+			}
+			if tcm.reloadTimer != nil {
+				tcm.reloadTimer.Stop()
+			}
+			tcm.reloadTimer = time.AfterFunc(testCaseReloadDelay, func() {
+				log.Printf("Test cases changed, reloading...")
+				tcm.reload()
+			})
+			tcm.indexMutex.Unlock()
+
+		case err, ok := <-tcm.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Test case watcher error: %v", err)
 		}
 	}
+}
 
-	return nil, fmt.Errorf("no matching test case found")
+// Close closes the file watcher
+// A pending reload is cancelled, and no reload is scheduled afterwards
+func (tcm *TestCaseManager) Close() error {
+	tcm.indexMutex.Lock()
+	tcm.closed = true
+	if tcm.reloadTimer != nil {
+		tcm.reloadTimer.Stop()
+	}
+	tcm.indexMutex.Unlock()
+
+	if tcm.watcher != nil {
+		return tcm.watcher.Close()
+	}
+	return nil
 }
 
-// loadTestCase loads a test case from a YAML file
-func (tcm *TestCaseManager) loadTestCase(filePath string) (*TestCaseConfig, error) {
+// loadTestCases loads the test cases of a YAML file, which holds either a single test case
+// or a testCases list; list reports which
+func (tcm *TestCaseManager) loadTestCases(filePath string) ([]*TestCaseConfig, bool, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read test case file: %w", err)
+		return nil, false, fmt.Errorf("failed to read test case file: %w", err)
+	}
+
+	var file testCaseFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, false, fmt.Errorf("failed to parse test case YAML: %w", err)
 	}
 
-	var testCase TestCaseConfig
-	if err := yaml.Unmarshal(data, &testCase); err != nil {
-		return nil, fmt.Errorf("failed to parse test case YAML: %w", err)
+	if len(file.TestCases) == 0 {
+		testCase := file.TestCaseConfig
+		if err := tcm.loadFiles(&testCase); err != nil {
+			return nil, false, err
+		}
+		return []*TestCaseConfig{&testCase}, false, nil
 	}
 
-	return &testCase, nil
+	testCases := make([]*TestCaseConfig, len(file.TestCases))
+	for i := range file.TestCases {
+		testCase := file.TestCases[i]
+		if err := tcm.loadFiles(&testCase); err != nil {
+			return nil, true, fmt.Errorf("testCases[%d]: %w", i, err)
+		}
+		testCases[i] = &testCase
+	}
+	return testCases, true, nil
+}
+
+// loadFiles loads the resource contents and content blocks of a test case that reference a file
+// File paths are relative to the testcases directory
+func (tcm *TestCaseManager) loadFiles(testCase *TestCaseConfig) error {
+	// Load resource contents that reference a file
+	for i, content := range testCase.Contents {
+		if content.File == "" {
+			continue
+		}
+		fileContent, err := loadResourceFile(filepath.Join(tcm.testCasesDir, content.File), content.MimeType)
+		if err != nil {
+			return err
+		}
+		fileContent.URI = content.URI
+		testCase.Contents[i] = fileContent
+	}
+
+	// Load content blocks that reference a file
+	for _, result := range testCase.results() {
+		if err := loadContentFiles(tcm.testCasesDir, result.Content); err != nil {
+			return err
+		}
+	}
+	for i := range testCase.Messages {
+		blocks := []ContentBlock{testCase.Messages[i].Content}
+		if err := loadContentFiles(tcm.testCasesDir, blocks); err != nil {
+			return err
+		}
+		testCase.Messages[i].Content = blocks[0]
+	}
+
+	return nil
+}
+
+// ValidateStructuredContent checks every test case of the given tools against the tool's
+// outputSchema and returns a description of each test case that does not conform
+func (tcm *TestCaseManager) ValidateStructuredContent(tools []Tool) []string {
+	var problems []string
+	for _, tool := range tools {
+		schema, ok := tool.OutputSchema.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, entry := range tcm.testCasesFor(tool.Name) {
+			for _, result := range entry.testCase.results() {
+				if err := checkStructuredContent(schema, result); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %v", entry.label(), err))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// checkStructuredContent validates a tool result's structuredContent against an outputSchema
+// Error results are exempt, as the schema describes successful results only
+func checkStructuredContent(outputSchema map[string]interface{}, result ToolResult) error {
+	if result.IsError {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return fmt.Errorf("structuredContent is required because the tool declares an outputSchema")
+	}
+	if err := validateSchema(outputSchema, result.StructuredContent); err != nil {
+		return fmt.Errorf("structuredContent does not match outputSchema: %w", err)
+	}
+	return nil
+}
+
+// results returns every tool result a test case can produce: its response and the
+// responses of its scripted reply branches
+// The results share their content slices with the test case, so files can be loaded in place
+func (tc *TestCaseConfig) results() []ToolResult {
+	results := []ToolResult{tc.Response}
+	if tc.Elicitation != nil {
+		for _, branch := range []*ToolResult{tc.Elicitation.Accept, tc.Elicitation.Decline, tc.Elicitation.Cancel} {
+			if branch != nil {
+				results = append(results, *branch)
+			}
+		}
+	}
+	if tc.Sampling != nil {
+		for _, reply := range tc.Sampling.Replies {
+			results = append(results, reply.Response)
+		}
+	}
+	return results
 }
 
 // matchArguments checks if the expected arguments match the actual arguments
@@ -125,6 +522,15 @@ func (tcm *TestCaseManager) matchArguments(expected map[string]interface{}, actu
 	// Check if all expected keys exist in actual and match
 	for key, expectedValue := range expected {
 		actualValue, exists := actual[key]
+
+		// Matchers such as {$exists: false} decide for themselves about missing keys
+		if ops, ok := isOperatorObject(expectedValue); ok {
+			if !tcm.matchOperators(ops, actualValue, exists) {
+				return false
+			}
+			continue
+		}
+
 		if !exists {
 			// If the key doesn't exist in actual, it's a mismatch
 			return false
@@ -139,8 +545,37 @@ func (tcm *TestCaseManager) matchArguments(expected map[string]interface{}, actu
 	return true
 }
 
-// valuesMatch compares two values, handling type conversions
+// matchRoots checks that every expected root URI is among the client's current roots
+func (tcm *TestCaseManager) matchRoots(expected []string, actual []Root) bool {
+	for _, uri := range expected {
+		found := false
+		for _, root := range actual {
+			if root.URI == uri {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesMatch compares two values, handling type conversions and matcher operators
 func (tcm *TestCaseManager) valuesMatch(expected, actual interface{}) bool {
+	if ops, ok := isOperatorObject(expected); ok {
+		return tcm.matchOperators(ops, actual, true)
+	}
+
+	// Nested objects match as a subset and arrays element by element, recursively
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		return tcm.matchObject(expectedValue, actual, false)
+	case []interface{}:
+		return tcm.matchOrdered(expectedValue, actual)
+	}
+
 	// Convert both to float64 for numeric comparison
 	expectedFloat, expectedIsNum := tcm.toFloat64(expected)
 	actualFloat, actualIsNum := tcm.toFloat64(actual)
@@ -163,8 +598,8 @@ func (tcm *TestCaseManager) valuesMatch(expected, actual interface{}) bool {
 		}
 	}
 
-	// Default: use == comparison
-	return expected == actual
+	// Default: deep comparison, which unlike == cannot panic on uncomparable values
+	return reflect.DeepEqual(expected, actual)
 }
 
 // toFloat64 converts numeric types to float64 for comparison
@@ -220,6 +655,9 @@ func (tcm *TestCaseManager) SaveTestCase(toolName string, testCaseNumber int, te
 		return fmt.Errorf("failed to write test case file: %w", err)
 	}
 
+	// Reload right away so the test case can be used without waiting for the watcher
+	tcm.reload()
+
 	return nil
 }
 
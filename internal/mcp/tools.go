@@ -12,12 +12,25 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// configReloadDelay is how long the config file must stay unchanged before it is reloaded
+const configReloadDelay = 100 * time.Millisecond
+
 // ToolManager handles tool loading, configuration, and file watching
+// It also holds the resources, resource templates and prompts declared in the same config file
 type ToolManager struct {
-	tools      map[string]Tool
-	toolsMutex sync.RWMutex
-	configPath string
-	watcher    *fsnotify.Watcher
+	server            ServerConfig
+	tools             map[string]Tool
+	toolOrder         []string // Tool names in config order, so lists are stable across calls
+	resources         []ResourceConfig
+	resourceTemplates []ResourceTemplateConfig
+	prompts           []PromptConfig
+	completions       []CompletionConfig
+	toolsMutex        sync.RWMutex
+	configPath        string
+	watcher           *fsnotify.Watcher
+	reloadTimer       *time.Timer
+	closed            bool
+	onReload          func()
 }
 
 // NewToolManager creates a new tool manager and loads tools from YAML
@@ -50,6 +63,20 @@ func NewToolManager(configPath string) (*ToolManager, error) {
 	return tm, nil
 }
 
+// SetReloadCallback registers a function called after every successful reload of the config file
+func (tm *ToolManager) SetReloadCallback(fn func()) {
+	tm.toolsMutex.Lock()
+	defer tm.toolsMutex.Unlock()
+	tm.onReload = fn
+}
+
+// GetServerConfig returns the server section of the config file (thread-safe)
+func (tm *ToolManager) GetServerConfig() ServerConfig {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return tm.server
+}
+
 // GetTool retrieves a tool by name (thread-safe)
 func (tm *ToolManager) GetTool(name string) (Tool, bool) {
 	tm.toolsMutex.RLock()
@@ -58,18 +85,134 @@ func (tm *ToolManager) GetTool(name string) (Tool, bool) {
 	return tool, exists
 }
 
-// GetAllTools returns all registered tools (thread-safe)
+// GetAllTools returns all registered tools in config order (thread-safe)
 func (tm *ToolManager) GetAllTools() []Tool {
 	tm.toolsMutex.RLock()
 	defer tm.toolsMutex.RUnlock()
 
-	tools := make([]Tool, 0, len(tm.tools))
-	for _, tool := range tm.tools {
-		tools = append(tools, tool)
+	tools := make([]Tool, 0, len(tm.toolOrder))
+	for _, name := range tm.toolOrder {
+		tools = append(tools, tm.tools[name])
 	}
 	return tools
 }
 
+// addTool registers a tool, keeping its position if a tool with the same name already exists
+// The caller must hold toolsMutex
+func (tm *ToolManager) addTool(tool Tool) {
+	if _, exists := tm.tools[tool.Name]; !exists {
+		tm.toolOrder = append(tm.toolOrder, tool.Name)
+	}
+	tm.tools[tool.Name] = tool
+}
+
+// GetResource retrieves a static resource by URI (thread-safe)
+func (tm *ToolManager) GetResource(uri string) (ResourceConfig, bool) {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	for _, resource := range tm.resources {
+		if resource.URI == uri {
+			return resource, true
+		}
+	}
+	return ResourceConfig{}, false
+}
+
+// GetAllResources returns all static resources in config order (thread-safe)
+func (tm *ToolManager) GetAllResources() []ResourceConfig {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return append([]ResourceConfig(nil), tm.resources...)
+}
+
+// GetAllResourceTemplates returns all resource templates in config order (thread-safe)
+func (tm *ToolManager) GetAllResourceTemplates() []ResourceTemplateConfig {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return append([]ResourceTemplateConfig(nil), tm.resourceTemplates...)
+}
+
+// HasResources reports whether any resources or resource templates are configured
+func (tm *ToolManager) HasResources() bool {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return len(tm.resources) > 0 || len(tm.resourceTemplates) > 0
+}
+
+// GetPrompt retrieves a prompt by name (thread-safe)
+func (tm *ToolManager) GetPrompt(name string) (PromptConfig, bool) {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	for _, prompt := range tm.prompts {
+		if prompt.Name == name {
+			return prompt, true
+		}
+	}
+	return PromptConfig{}, false
+}
+
+// GetAllPrompts returns all prompts in config order (thread-safe)
+func (tm *ToolManager) GetAllPrompts() []PromptConfig {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return append([]PromptConfig(nil), tm.prompts...)
+}
+
+// HasPrompts reports whether any prompts are configured
+func (tm *ToolManager) HasPrompts() bool {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return len(tm.prompts) > 0
+}
+
+// GetResourceTemplate retrieves a resource template by its URI template (thread-safe)
+func (tm *ToolManager) GetResourceTemplate(uriTemplate string) (ResourceTemplateConfig, bool) {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	for _, template := range tm.resourceTemplates {
+		if template.URITemplate == uriTemplate {
+			return template, true
+		}
+	}
+	return ResourceTemplateConfig{}, false
+}
+
+// GetCompletion retrieves the completion config for an argument of a prompt ("ref/prompt", by name)
+// or a resource template ("ref/resource", by URI template) (thread-safe)
+func (tm *ToolManager) GetCompletion(refType, ref, argument string) (CompletionConfig, bool) {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	for _, completion := range tm.completions {
+		if completion.Argument != argument {
+			continue
+		}
+		if (refType == "ref/prompt" && completion.Prompt == ref) ||
+			(refType == "ref/resource" && completion.ResourceTemplate == ref) {
+			return completion, true
+		}
+	}
+	return CompletionConfig{}, false
+}
+
+// GetAllCompletions returns all completions in config order (thread-safe)
+func (tm *ToolManager) GetAllCompletions() []CompletionConfig {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return append([]CompletionConfig(nil), tm.completions...)
+}
+
+// HasCompletions reports whether any completions are configured
+func (tm *ToolManager) HasCompletions() bool {
+	tm.toolsMutex.RLock()
+	defer tm.toolsMutex.RUnlock()
+	return len(tm.completions) > 0
+}
+
+// GetConfigDir returns the directory containing the config file
+func (tm *ToolManager) GetConfigDir() string {
+	return filepath.Dir(tm.configPath)
+}
+
 // loadToolsFromYAML loads tools from the YAML configuration file
 func (tm *ToolManager) loadToolsFromYAML() error {
 	data, err := os.ReadFile(tm.configPath)
@@ -85,21 +228,64 @@ func (tm *ToolManager) loadToolsFromYAML() error {
 	tm.toolsMutex.Lock()
 	defer tm.toolsMutex.Unlock()
 
+	tm.server = config.Server
+	if len(tm.server.ProtocolVersions) > 0 {
+		log.Printf("Supported protocol versions: %v (strict: %t)", tm.server.ProtocolVersions, tm.server.StrictProtocolVersion)
+	}
+
 	// Clear existing tools
 	tm.tools = make(map[string]Tool)
+	tm.toolOrder = nil
 
 	// Load tools from YAML
 	for _, toolConfig := range config.Tools {
 		tool := Tool{
 			Name:            toolConfig.Name,
+			Title:           toolConfig.Title,
 			Description:     toolConfig.Description,
 			InputSchema:     toolConfig.InputSchema,
+			Annotations:     toolConfig.Annotations,
+			Meta:            toolConfig.Meta,
 			DefaultTestCase: toolConfig.DefaultTestCase,
+			Match:           toolConfig.Match,
+		}
+		if len(toolConfig.OutputSchema) > 0 {
+			tool.OutputSchema = toolConfig.OutputSchema
 		}
-		tm.tools[toolConfig.Name] = tool
+		tm.addTool(tool)
 		log.Printf("Loaded tool: %s (defaultTestCase: %d)", toolConfig.Name, toolConfig.DefaultTestCase)
 	}
 
+	// Load resources and resource templates from YAML
+	tm.resources = config.Resources
+	for _, resource := range tm.resources {
+		log.Printf("Loaded resource: %s (%s)", resource.Name, resource.URI)
+	}
+	tm.resourceTemplates = config.ResourceTemplates
+	for _, template := range tm.resourceTemplates {
+		log.Printf("Loaded resource template: %s (%s)", template.Name, template.URITemplate)
+	}
+
+	// Load prompts from YAML
+	tm.prompts = config.Prompts
+	for _, prompt := range tm.prompts {
+		log.Printf("Loaded prompt: %s (defaultTestCase: %d)", prompt.Name, prompt.DefaultTestCase)
+	}
+
+	// Load completions from YAML
+	tm.completions = config.Completions
+	for i, completion := range tm.completions {
+		switch completion.Mode {
+		case "":
+			tm.completions[i].Mode = CompletionModePrefix
+		case CompletionModePrefix, CompletionModeStatic, CompletionModeTestCase:
+		default:
+			log.Printf("Warning: unknown completion mode %q for argument %s, using %s", completion.Mode, completion.Argument, CompletionModePrefix)
+			tm.completions[i].Mode = CompletionModePrefix
+		}
+		log.Printf("Loaded completion: %s%s argument %s (mode: %s)", completion.Prompt, completion.ResourceTemplate, completion.Argument, tm.completions[i].Mode)
+	}
+
 	return nil
 }
 
@@ -180,7 +366,7 @@ func (tm *ToolManager) registerDefaultTools() {
 	tm.toolsMutex.Lock()
 	defer tm.toolsMutex.Unlock()
 
-	tm.tools["mock_echo"] = Tool{
+	tm.addTool(Tool{
 		Name:        "mock_echo",
 		Description: "Echoes back the input message",
 		InputSchema: map[string]interface{}{
@@ -193,9 +379,9 @@ func (tm *ToolManager) registerDefaultTools() {
 			},
 			"required": []string{"message"},
 		},
-	}
+	})
 
-	tm.tools["mock_calculator"] = Tool{
+	tm.addTool(Tool{
 		Name:        "mock_calculator",
 		Description: "Performs basic arithmetic operations",
 		InputSchema: map[string]interface{}{
@@ -217,9 +403,9 @@ func (tm *ToolManager) registerDefaultTools() {
 			},
 			"required": []string{"operation", "a", "b"},
 		},
-	}
+	})
 
-	tm.tools["mock_delay"] = Tool{
+	tm.addTool(Tool{
 		Name:        "mock_delay",
 		Description: "Simulates a delayed operation",
 		InputSchema: map[string]interface{}{
@@ -232,7 +418,7 @@ func (tm *ToolManager) registerDefaultTools() {
 			},
 			"required": []string{"seconds"},
 		},
-	}
+	})
 }
 
 // startFileWatcher starts watching the config file for changes
@@ -266,16 +452,19 @@ func (tm *ToolManager) watchFileChanges() {
 			}
 
 			// Only reload on write/rename events for the config file
+			// A save usually produces several events, so the reload waits until they stop
 			if (event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Rename == fsnotify.Rename) &&
 				event.Name == tm.configPath {
-				log.Printf("Config file changed, reloading tools...")
-				// Small delay to ensure file write is complete
-				time.Sleep(100 * time.Millisecond)
-				if err := tm.loadToolsFromYAML(); err != nil {
-					log.Printf("Error reloading tools: %v", err)
-				} else {
-					log.Printf("Tools reloaded successfully")
+				tm.toolsMutex.Lock()
+				if tm.closed {
+					tm.toolsMutex.Unlock()
+					return
+				}
+				if tm.reloadTimer != nil {
+					tm.reloadTimer.Stop()
 				}
+				tm.reloadTimer = time.AfterFunc(configReloadDelay, tm.reload)
+				tm.toolsMutex.Unlock()
 			}
 
 		case err, ok := <-tm.watcher.Errors:
@@ -287,8 +476,30 @@ func (tm *ToolManager) watchFileChanges() {
 	}
 }
 
-// Close closes the file watcher
+// reload reloads the config file and calls the reload callback if it succeeds
+func (tm *ToolManager) reload() {
+	log.Printf("Config file changed, reloading tools...")
+	if err := tm.loadToolsFromYAML(); err != nil {
+		log.Printf("Error reloading tools: %v", err)
+		return
+	}
+	log.Printf("Tools reloaded successfully")
+	tm.toolsMutex.RLock()
+	onReload := tm.onReload
+	tm.toolsMutex.RUnlock()
+	if onReload != nil {
+		onReload()
+	}
+}
+
+// Close closes the file watcher and cancels any pending reload
 func (tm *ToolManager) Close() error {
+	tm.toolsMutex.Lock()
+	tm.closed = true
+	if tm.reloadTimer != nil {
+		tm.reloadTimer.Stop()
+	}
+	tm.toolsMutex.Unlock()
 	if tm.watcher != nil {
 		return tm.watcher.Close()
 	}
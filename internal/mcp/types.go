@@ -1,6 +1,9 @@
 package mcp
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // MCP Protocol Types
 type MCPRequest struct {
@@ -8,6 +11,16 @@ type MCPRequest struct {
 	ID      interface{}     `json:"id,omitempty"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+
+	// Result and Error are set instead of Method when the message is the client's
+	// response to a server-initiated request
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *MCPError       `json:"error,omitempty"`
+}
+
+// isResponse reports whether the message is a response rather than a request or notification
+func (r *MCPRequest) isResponse() bool {
+	return r.Method == "" && r.ID != nil && (r.Result != nil || r.Error != nil)
 }
 
 type MCPResponse struct {
@@ -17,6 +30,21 @@ type MCPResponse struct {
 	Error   *MCPError   `json:"error,omitempty"`
 }
 
+// MCPNotification is a JSON-RPC notification sent from the server to the client
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// MCPServerRequest is a JSON-RPC request sent from the server to the client
+type MCPServerRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 type MCPError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
@@ -25,10 +53,26 @@ type MCPError struct {
 
 // Tool Types
 type Tool struct {
-	Name            string      `json:"name"`
-	Description     string      `json:"description"`
-	InputSchema     interface{} `json:"inputSchema"`
-	DefaultTestCase int         `json:"defaultTestCase,omitempty"` // 0 = no default, 1+ = use test-case-N as default
+	Name            string                 `json:"name"`
+	Title           string                 `json:"title,omitempty"` // Human-readable display name (2025-06-18)
+	Description     string                 `json:"description"`
+	InputSchema     interface{}            `json:"inputSchema"`
+	OutputSchema    interface{}            `json:"outputSchema,omitempty"`    // Schema of the tool's structuredContent (2025-06-18)
+	Annotations     *ToolAnnotations       `json:"annotations,omitempty"`     // Behaviour hints (2025-03-26)
+	Meta            map[string]interface{} `json:"_meta,omitempty"`           // Arbitrary metadata (2025-06-18)
+	DefaultTestCase int                    `json:"defaultTestCase,omitempty"` // 0 = no default, 1+ = use test-case-N as default
+	Match           string                 `json:"-"`                         // Match mode for test cases that set none: subset or exact
+}
+
+// ToolAnnotations describe a tool's behaviour to the client
+// The hints are pointers so an unset hint is left out rather than sent as false,
+// as the protocol gives unset hints their own defaults (e.g. destructiveHint defaults to true)
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty" yaml:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty" yaml:"readOnlyHint,omitempty"`       // The tool does not modify its environment
+	DestructiveHint *bool  `json:"destructiveHint,omitempty" yaml:"destructiveHint,omitempty"` // Updates may be destructive (only meaningful when not read-only)
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty" yaml:"idempotentHint,omitempty"`   // Repeated calls with the same arguments have no additional effect
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty" yaml:"openWorldHint,omitempty"`     // The tool interacts with external entities
 }
 
 type ToolCall struct {
@@ -37,13 +81,89 @@ type ToolCall struct {
 }
 
 type ToolResult struct {
-	Content []ContentBlock `json:"content"`
-	IsError bool           `json:"isError,omitempty"`
+	Content           []ContentBlock         `json:"content" yaml:"content"`
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty" yaml:"structuredContent,omitempty"` // Must conform to the tool's outputSchema
+	IsError           bool                   `json:"isError,omitempty" yaml:"isError,omitempty"`
 }
 
+// ContentBlock is one item of tool result or prompt message content
+// Type is text, image, audio, resource (embedded) or resource_link
 type ContentBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text,omitempty"`
+	Type string `json:"type" yaml:"type"`
+	Text string `json:"text,omitempty" yaml:"text,omitempty"`
+
+	// image and audio: base64-encoded data; mimeType is also used by resource_link
+	Data     string `json:"data,omitempty" yaml:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty" yaml:"mimeType,omitempty"`
+
+	// resource: the embedded resource contents
+	Resource *ResourceContents `json:"resource,omitempty" yaml:"resource,omitempty"`
+
+	// resource_link: a reference to a resource the client can read
+	URI         string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Size        int64  `json:"size,omitempty" yaml:"size,omitempty"`
+
+	Annotations *Annotations `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	File        string       `json:"-" yaml:"file,omitempty"` // Optional: file to load text/data/resource from, relative to the testcases directory
+}
+
+// Annotations tell the client how to use or display a content block
+type Annotations struct {
+	Audience     []string `json:"audience,omitempty" yaml:"audience,omitempty"` // user and/or assistant
+	Priority     *float64 `json:"priority,omitempty" yaml:"priority,omitempty"` // 0 (optional) to 1 (required)
+	LastModified string   `json:"lastModified,omitempty" yaml:"lastModified,omitempty"`
+}
+
+// Resource Types
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri" yaml:"uri,omitempty"`
+	MimeType string `json:"mimeType,omitempty" yaml:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty" yaml:"text,omitempty"`
+	Blob     string `json:"blob,omitempty" yaml:"blob,omitempty"` // base64-encoded binary data
+	File     string `json:"-" yaml:"file,omitempty"`              // Optional: file to load text/blob from, relative to the testcases directory
+}
+
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// Prompt Types
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string       `json:"role" yaml:"role"`
+	Content ContentBlock `json:"content" yaml:"content"`
+}
+
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
 }
 
 // Initialize Types
@@ -62,18 +182,147 @@ type InitializeResult struct {
 // YAML Configuration Types
 type ToolConfig struct {
 	Name            string                 `yaml:"name"`
+	Title           string                 `yaml:"title,omitempty"` // Optional: human-readable display name
 	Description     string                 `yaml:"description"`
 	InputSchema     map[string]interface{} `yaml:"inputSchema"`
+	OutputSchema    map[string]interface{} `yaml:"outputSchema,omitempty"`    // Optional: schema test case structuredContent is validated against
+	Annotations     *ToolAnnotations       `yaml:"annotations,omitempty"`     // Optional: behaviour hints such as destructiveHint
+	Meta            map[string]interface{} `yaml:"_meta,omitempty"`           // Optional: metadata returned as-is in tools/list
 	Handler         string                 `yaml:"handler,omitempty"`         // Optional: custom handler type
 	DefaultTestCase int                    `yaml:"defaultTestCase,omitempty"` // 0 = no default, 1+ = use test-case-N as default
+	Match           string                 `yaml:"match,omitempty"`           // Optional: match mode for the tool's test cases (subset or exact)
+}
+
+type ResourceConfig struct {
+	URI             string `yaml:"uri"`
+	Name            string `yaml:"name"`
+	Description     string `yaml:"description,omitempty"`
+	MimeType        string `yaml:"mimeType,omitempty"`
+	Text            string `yaml:"text,omitempty"`            // Optional: inline text contents
+	Blob            string `yaml:"blob,omitempty"`            // Optional: inline base64-encoded contents
+	File            string `yaml:"file,omitempty"`            // Optional: file to serve, relative to the config file
+	DefaultTestCase int    `yaml:"defaultTestCase,omitempty"` // 0 = no default, 1+ = use test-case-N as default
+}
+
+type ResourceTemplateConfig struct {
+	URITemplate     string `yaml:"uriTemplate"`
+	Name            string `yaml:"name"`
+	Description     string `yaml:"description,omitempty"`
+	MimeType        string `yaml:"mimeType,omitempty"`
+	DefaultTestCase int    `yaml:"defaultTestCase,omitempty"` // 0 = no default, 1+ = use test-case-N as default
+}
+
+type PromptConfig struct {
+	Name            string           `yaml:"name"`
+	Description     string           `yaml:"description,omitempty"`
+	Arguments       []PromptArgument `yaml:"arguments,omitempty"`
+	DefaultTestCase int              `yaml:"defaultTestCase,omitempty"` // 0 = no default, 1+ = use test-case-N as default
+}
+
+// CompletionConfig supplies completion/complete values for one prompt argument or resource template variable
+type CompletionConfig struct {
+	Prompt           string   `yaml:"prompt,omitempty"`           // Prompt whose argument is completed
+	ResourceTemplate string   `yaml:"resourceTemplate,omitempty"` // URI template whose variable is completed
+	Argument         string   `yaml:"argument"`                   // Argument or template variable name
+	Mode             string   `yaml:"mode,omitempty"`             // prefix (default), static or testcase
+	Values           []string `yaml:"values,omitempty"`           // Candidate values for the prefix and static modes
+}
+
+// ServerConfig holds protocol-level settings from the server section of tools.yaml
+type ServerConfig struct {
+	ProtocolVersions      []string `yaml:"protocolVersions,omitempty"`      // Supported versions, preferred first (default: all known versions)
+	StrictProtocolVersion bool     `yaml:"strictProtocolVersion,omitempty"` // Reject unsupported versions instead of offering the preferred one
+	PageSize              int      `yaml:"pageSize,omitempty"`              // Items per page of tools/list, resources/list, resources/templates/list and prompts/list (0 = no pagination)
 }
 
 type ToolsConfig struct {
-	Tools []ToolConfig `yaml:"tools"`
+	Server            ServerConfig             `yaml:"server,omitempty"`
+	Tools             []ToolConfig             `yaml:"tools"`
+	Resources         []ResourceConfig         `yaml:"resources,omitempty"`
+	ResourceTemplates []ResourceTemplateConfig `yaml:"resourceTemplates,omitempty"`
+	Prompts           []PromptConfig           `yaml:"prompts,omitempty"`
+	Completions       []CompletionConfig       `yaml:"completions,omitempty"`
 }
 
 // Test Case Configuration
 type TestCaseConfig struct {
+	Name     string                 `yaml:"name,omitempty"`     // Shown in logs; the file name is used when empty
+	Priority int                    `yaml:"priority,omitempty"` // Higher priority test cases are tried first (default 0)
 	Input    map[string]interface{} `yaml:"input"`
+	Match    string                 `yaml:"match,omitempty"` // subset (the default) or exact, which rejects arguments not in input
+	Roots    []string               `yaml:"roots,omitempty"` // Root URIs the client must have reported for the test case to match
 	Response ToolResult             `yaml:"response"`
+	Logs     []LogMessage           `yaml:"logs,omitempty"`     // notifications/message sent before the response
+	Progress []ProgressStep         `yaml:"progress,omitempty"` // notifications/progress sent before the response
+	Delay    time.Duration          `yaml:"delay,omitempty"`    // Wait before responding (e.g. 2s); the call can be cancelled meanwhile
+	Sampling *SamplingStep          `yaml:"sampling,omitempty"` // sampling/createMessage sent to the client before the response
+	// elicitation/create sent to the client before the response (and before any sampling)
+	Elicitation *ElicitationStep   `yaml:"elicitation,omitempty"`
+	Contents    []ResourceContents `yaml:"contents,omitempty"` // resources/read response for resource test cases
+
+	Completion []string `yaml:"completion,omitempty"` // completion/complete values for completion test cases
+
+	// prompts/get response for prompt test cases
+	Description string          `yaml:"description,omitempty"`
+	Messages    []PromptMessage `yaml:"messages,omitempty"`
+}
+
+// ProgressStep is one scripted notifications/progress message in a test case
+type ProgressStep struct {
+	Progress float64       `yaml:"progress"`
+	Total    float64       `yaml:"total,omitempty"`
+	Message  string        `yaml:"message,omitempty"`
+	Delay    time.Duration `yaml:"delay,omitempty"` // Wait before sending this step (e.g. 500ms)
+}
+
+// LogMessage is one scripted notifications/message log line in a test case
+type LogMessage struct {
+	Level  string        `yaml:"level"` // debug, info, notice, warning, error, critical, alert or emergency
+	Logger string        `yaml:"logger,omitempty"`
+	Data   interface{}   `yaml:"data"`            // Any JSON-serializable value
+	Delay  time.Duration `yaml:"delay,omitempty"` // Wait before sending this line (e.g. 200ms)
+}
+
+// SamplingStep is a scripted sampling/createMessage request in a tool test case
+// The client's reply selects the first matching entry of Replies, or falls back to the test
+// case's response; either way {{sampling.*}} placeholders are filled in from the reply
+type SamplingStep struct {
+	Messages         []SamplingMessage      `yaml:"messages"`
+	SystemPrompt     string                 `yaml:"systemPrompt,omitempty"`
+	IncludeContext   string                 `yaml:"includeContext,omitempty"` // none, thisServer or allServers
+	Temperature      *float64               `yaml:"temperature,omitempty"`
+	MaxTokens        int                    `yaml:"maxTokens,omitempty"` // Defaults to 1000
+	StopSequences    []string               `yaml:"stopSequences,omitempty"`
+	ModelPreferences map[string]interface{} `yaml:"modelPreferences,omitempty"`
+	Metadata         map[string]interface{} `yaml:"metadata,omitempty"`
+	Timeout          time.Duration          `yaml:"timeout,omitempty"` // How long to wait for the reply (default 60s)
+	Replies          []SamplingReply        `yaml:"replies,omitempty"`
+}
+
+// SamplingMessage is one message of a sampling/createMessage request
+type SamplingMessage struct {
+	Role    string       `json:"role" yaml:"role"`
+	Content ContentBlock `json:"content" yaml:"content"`
+}
+
+// SamplingReply picks the tool result for a matching sampling reply
+// All conditions that are set must hold; an entry without conditions matches any reply
+type SamplingReply struct {
+	Text     string     `yaml:"text,omitempty"`     // Reply text equals this
+	Contains string     `yaml:"contains,omitempty"` // Reply text contains this
+	Pattern  string     `yaml:"pattern,omitempty"`  // Reply text matches this regular expression
+	Model    string     `yaml:"model,omitempty"`    // Model that produced the reply
+	Response ToolResult `yaml:"response"`
+}
+
+// ElicitationStep is a scripted elicitation/create request in a tool test case
+// The tool result depends on the user's action; {{elicitation.*}} placeholders are filled in
+// from the action and the accepted content
+type ElicitationStep struct {
+	Message         string                 `yaml:"message"`
+	RequestedSchema map[string]interface{} `yaml:"requestedSchema"`
+	Timeout         time.Duration          `yaml:"timeout,omitempty"` // How long to wait for the user (default 5m)
+	Accept          *ToolResult            `yaml:"accept,omitempty"`  // Result when the user accepts (default: the test case's response)
+	Decline         *ToolResult            `yaml:"decline,omitempty"` // Result when the user declines (default: an error result)
+	Cancel          *ToolResult            `yaml:"cancel,omitempty"`  // Result when the user cancels (default: an error result)
 }
@@ -158,6 +158,7 @@ func (wh *WebhookHandler) handlePushEvent(payload []byte) error {
 		return fmt.Errorf("failed to sync repository: %w", err)
 	}
 
+	// The tool and test case managers pick up the synced files through their file watchers
 	log.Printf("Repository synced successfully via webhook")
 	return nil
 }